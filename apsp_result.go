@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// ---------------- All-pairs result and cache ----------------
+
+// APSPResult is a computed all-pairs solution answering point-to-point queries.
+// pred[i][j] is the vertex before j on the shortest i → j path (i for j == i)
+// and -1 when j is unreachable; dist[i][j] is meaningful only where pred is set.
+type APSPResult struct {
+	Algo     string
+	Weights  string // exact weight type, "" for float64
+	Paths    string // path semiring, "" for shortest paths
+	Version  uint64 // graph version the result was computed for
+	NegCycle bool
+	Stats    SolverStats
+	Elapsed  time.Duration
+	Updates  int         // incremental edge updates applied since the full solve
+	Update   SolverStats // counters of the last incremental update
+
+	dist Matrix[float64]
+	pred Matrix[int]
+	text Matrix[string] // exact distances, set only when Weights != ""
+}
+
+func (r *APSPResult) N() int { return r.dist.N() }
+
+func (r *APSPResult) Reachable(i, j int) bool { return r.pred.At(i, j) >= 0 }
+
+// Dist returns the i → j distance and whether j is reachable from i.
+func (r *APSPResult) Dist(i, j int) (float64, bool) {
+	return r.dist.At(i, j), r.Reachable(i, j)
+}
+
+// DistText formats the i → j distance, exactly when the result was computed
+// with exact weights; ∞ when unreachable.
+func (r *APSPResult) DistText(i, j int) string {
+	if r.Weights != "" {
+		return r.text.At(i, j)
+	}
+	return distText(r.Dist(i, j))
+}
+
+// Key is the cache key of the solver run that produced r.
+func (r *APSPResult) Key() string { return solverCacheKey(r.Algo, r.Weights, r.Paths) }
+
+// Path returns the i → j shortest path as 1-based vertex numbers, nil if none.
+func (r *APSPResult) Path(i, j int) []int {
+	if r.NegCycle || !r.Reachable(i, j) {
+		return nil
+	}
+	return reconstructFromPrev(r.pred.Row(i), i, j)
+}
+
+// NegCycleVertices lists (0-based) vertices lying on a negative cycle.
+func (r *APSPResult) NegCycleVertices() []int {
+	var out []int
+	for i := 0; i < r.N(); i++ {
+		if r.dist.At(i, i) < 0 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// apspCache keeps the last result of every solver until the graph changes.
+type apspCache struct {
+	g       *Graph
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	version uint64
+	res     *APSPResult
+	err     error
+}
+
+func newAPSPCache(g *Graph) *apspCache {
+	return &apspCache{g: g, entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the result of solver key for the current graph, computing it
+// only if the graph changed since the last call; cached reports a hit.
+func (c *apspCache) Get(key string) (res *APSPResult, cached bool, err error) {
+	if e, ok := c.entries[key]; ok && e.version == c.g.Version() {
+		return e.res, true, e.err
+	}
+	algo, weights, paths := splitSolverKey(key)
+	sv, ok := solverByKey(algo)
+	if !ok {
+		return nil, false, errUnknownSolver(algo)
+	}
+	res, err = runSolver(sv, weights, paths, c.g)
+	c.entries[key] = cacheEntry{version: c.g.Version(), res: res, err: err}
+	return res, false, err
+}
+
+// solverCacheKey names a solver run: "floyd" for float64 shortest paths,
+// "floyd:rat" for exact weights, "floyd+widest" for another path semiring.
+func solverCacheKey(algo, weights, paths string) string {
+	key := algo
+	if weights != "" && weights != "float" {
+		key += ":" + weights
+	}
+	if paths != "" && paths != "shortest" {
+		key += "+" + paths
+	}
+	return key
+}
+
+func splitSolverKey(key string) (algo, weights, paths string) {
+	key, paths, _ = strings.Cut(key, "+")
+	algo, weights, _ = strings.Cut(key, ":")
+	return algo, weights, paths
+}
+
+// runSolver runs sv on g with counters and timing, using exact arithmetic
+// when weights names one of weightKinds other than float and the semiring
+// closure when paths names one of pathSemirings other than shortest.
+func runSolver(sv apspSolver, weights, paths string, g *Graph) (*APSPResult, error) {
+	var st SolverStats
+	start := time.Now()
+	var res *APSPResult
+	var err error
+	if weights == "float" {
+		weights = ""
+	}
+	if paths == "shortest" {
+		paths = ""
+	}
+	switch {
+	case paths != "" && (sv.Key != "floyd" || weights != ""):
+		return nil, errSemiringSolver
+	case paths != "":
+		res, err = solveClosure(g, paths, &st)
+	case weights == "":
+		res, err = sv.Run(g, &st)
+	default:
+		res, err = solveExact(g, sv.Key, weights, &st)
+	}
+	if res != nil {
+		res.Algo = sv.Key
+		res.Weights = weights
+		res.Paths = paths
+		res.Version = g.Version()
+		res.Stats = st
+		res.Elapsed = time.Since(start)
+	}
+	return res, err
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Only vertices on the cycle are reported: 3 is reachable from the cycle and
+// 4 reaches it, yet neither lies on it.
+func TestNegCycleVertices(t *testing.T) {
+	g, err := readGraphCSV(strings.NewReader("1;2;1\n2;1;-3\n2;3;1\n4;1;1\n"), "edges", ';')
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, _ := solverByKey("floyd")
+	res, err := runSolver(sv, "", "", g)
+	if !errors.Is(err, errNegativeCycle) || !res.NegCycle {
+		t.Fatalf("err = %v, NegCycle = %v, want a negative cycle", err, res.NegCycle)
+	}
+	if got := res.NegCycleVertices(); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("NegCycleVertices = %v, want [0 1]", got)
+	}
+	if p := res.Path(3, 2); p != nil {
+		t.Errorf("Path(4, 3) = %v, want nil under a negative cycle", p)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Betweenness centrality ----------------
+
+// centrality holds betweenness values: the number of shortest s → t paths
+// (s ≠ t, split evenly between equally short ones) that pass through a vertex
+// other than s and t, or along an edge.
+type centrality struct {
+	Vertex []float64
+	Edge   map[[2]int]float64
+}
+
+// sameLength treats path lengths that differ only by rounding as equal, so
+// 0.1+0.2 and 0.3 both count as shortest.
+func sameLength(a, b float64) bool {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return math.Abs(a-b) <= 1e-12*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// errZeroCycle rejects graphs where a zero-weight cycle lies on shortest
+// paths: going round it gives infinitely many equally short walks.
+var errZeroCycle = errors.New("цикл нулевого веса: число кратчайших путей не определено")
+
+// betweenness is Brandes' algorithm for weighted digraphs: one Dijkstra per
+// source, then shortest paths are counted over the shortest-path DAG in
+// topological order and dependencies accumulated in reverse. The DAG order is
+// needed for zero-weight edges, which join vertices at the same distance in
+// any settle order. It needs non-negative weights.
+func betweenness(g *Graph) (centrality, error) {
+	if g.HasNegativeEdge() {
+		return centrality{}, errNegativeEdges
+	}
+	n := g.n
+	c := centrality{Vertex: make([]float64, n), Edge: make(map[[2]int]float64)}
+	out := g.arcs()
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	succs := make([][]int, n)
+	for s := 0; s < n; s++ {
+		dist, prev := dijkstraArcs(out, s, nil)
+		for v := 0; v < n; v++ {
+			sigma[v], delta[v] = 0, 0
+			preds[v], succs[v] = preds[v][:0], succs[v][:0]
+		}
+		for v := 0; v < n; v++ {
+			if prev[v] < 0 {
+				continue
+			}
+			for _, e := range out[v] {
+				if sameLength(dist[v]+e.w, dist[e.to]) {
+					preds[e.to] = append(preds[e.to], v)
+					succs[v] = append(succs[v], e.to)
+				}
+			}
+		}
+		// Kahn's algorithm; order ends up short of the reachable vertices
+		// only when the DAG has a (zero-weight) cycle
+		indeg := make([]int, n)
+		reached := 0
+		for v := 0; v < n; v++ {
+			indeg[v] = len(preds[v])
+			if prev[v] >= 0 {
+				reached++
+			}
+		}
+		if indeg[s] > 0 {
+			return centrality{}, errZeroCycle
+		}
+		sigma[s] = 1
+		order := []int{s}
+		for k := 0; k < len(order); k++ {
+			v := order[k]
+			for _, w := range succs[v] {
+				sigma[w] += sigma[v]
+				if indeg[w]--; indeg[w] == 0 {
+					order = append(order, w)
+				}
+			}
+		}
+		if len(order) < reached {
+			return centrality{}, errZeroCycle
+		}
+		for k := len(order) - 1; k >= 0; k-- {
+			w := order[k]
+			for _, v := range preds[w] {
+				share := sigma[v] / sigma[w] * (1 + delta[w])
+				c.Edge[[2]int{v, w}] += share
+				delta[v] += share
+			}
+			if w != s {
+				c.Vertex[w] += delta[w]
+			}
+		}
+	}
+	return c, nil
+}
+
+// normVertex scales vertex betweenness to [0, 1] by the number of ordered
+// pairs not containing the vertex.
+func (c centrality) normVertex(v int) float64 {
+	n := len(c.Vertex)
+	if n < 3 {
+		return 0
+	}
+	return c.Vertex[v] / float64((n-1)*(n-2))
+}
+
+// centralityScale maps betweenness onto drawing sizes: the most central
+// vertex is drawn 1.8× larger, the most central edge 3× thicker.
+type centralityScale struct {
+	vertex []float64
+	edge   map[[2]int]float64
+}
+
+func newCentralityScale(c centrality) *centralityScale {
+	s := &centralityScale{vertex: make([]float64, len(c.Vertex)), edge: make(map[[2]int]float64, len(c.Edge))}
+	var maxV, maxE float64
+	for _, v := range c.Vertex {
+		maxV = math.Max(maxV, v)
+	}
+	for _, v := range c.Edge {
+		maxE = math.Max(maxE, v)
+	}
+	for i, v := range c.Vertex {
+		s.vertex[i] = 1
+		if maxV > 0 {
+			s.vertex[i] += 0.8 * v / maxV
+		}
+	}
+	for k, v := range c.Edge {
+		if maxE > 0 {
+			s.edge[k] = 1 + 2*v/maxE
+		}
+	}
+	return s
+}
+
+// vertexK is the radius factor of vertex i; a nil scale draws everything at
+// the normal size.
+func (s *centralityScale) vertexK(i int) float64 {
+	if s == nil || i >= len(s.vertex) {
+		return 1
+	}
+	return s.vertex[i]
+}
+
+// edgeK is the stroke factor of edge u → v.
+func (s *centralityScale) edgeK(u, v int) float64 {
+	if s == nil {
+		return 1
+	}
+	if k, ok := s.edge[[2]int{u, v}]; ok {
+		return k
+	}
+	return 1
+}
+
+// centralityPanel lists vertex and edge betweenness, most central first.
+type centralityPanel struct {
+	box     fyne.CanvasObject
+	info    *widget.Label
+	vertexT *widget.Table
+	edgeT   *widget.Table
+	scale   *widget.Check
+
+	c     centrality
+	verts []int
+	edges [][2]int
+
+	onScale func()
+}
+
+func newCentralityPanel() *centralityPanel {
+	p := &centralityPanel{info: widget.NewLabel("")}
+	header := func(titles ...string) (func() fyne.CanvasObject, func(widget.TableCellID, fyne.CanvasObject)) {
+		return func() fyne.CanvasObject {
+				return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			}, func(id widget.TableCellID, co fyne.CanvasObject) {
+				if id.Col >= 0 && id.Col < len(titles) {
+					co.(*widget.Label).SetText(titles[id.Col])
+				}
+			}
+	}
+	p.vertexT = widget.NewTable(
+		func() (int, int) { return len(p.verts), 3 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			var txt string
+			if id.Row < len(p.verts) {
+				v := p.verts[id.Row]
+				switch id.Col {
+				case 0:
+					txt = vertexName(v)
+				case 1:
+					txt = strconv.FormatFloat(p.c.Vertex[v], 'g', 6, 64)
+				case 2:
+					txt = strconv.FormatFloat(p.c.normVertex(v), 'f', 4, 64)
+				}
+			}
+			co.(*widget.Label).SetText(txt)
+		},
+	)
+	p.vertexT.ShowHeaderRow = true
+	p.vertexT.CreateHeader, p.vertexT.UpdateHeader = header("Вершина", "Посредничество", "Нормир.")
+	p.vertexT.SetColumnWidth(0, 80)
+	p.vertexT.SetColumnWidth(1, 130)
+	p.vertexT.SetColumnWidth(2, 90)
+
+	p.edgeT = widget.NewTable(
+		func() (int, int) { return len(p.edges), 2 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			var txt string
+			if id.Row < len(p.edges) {
+				e := p.edges[id.Row]
+				if id.Col == 0 {
+					txt = vertexName(e[0]) + " → " + vertexName(e[1])
+				} else {
+					txt = strconv.FormatFloat(p.c.Edge[e], 'g', 6, 64)
+				}
+			}
+			co.(*widget.Label).SetText(txt)
+		},
+	)
+	p.edgeT.ShowHeaderRow = true
+	p.edgeT.CreateHeader, p.edgeT.UpdateHeader = header("Дуга", "Посредничество")
+	p.edgeT.SetColumnWidth(0, 100)
+	p.edgeT.SetColumnWidth(1, 130)
+
+	p.scale = widget.NewCheck("Размер вершин и толщина дуг в редакторе по посредничеству", func(bool) {
+		if p.onScale != nil {
+			p.onScale()
+		}
+	})
+	p.box = container.NewBorder(container.NewVBox(p.info, p.scale), nil, nil, nil,
+		container.NewHSplit(p.vertexT, p.edgeT))
+	return p
+}
+
+// Update recomputes betweenness for g.
+func (p *centralityPanel) Update(g *Graph) {
+	c, err := betweenness(g)
+	p.c, p.verts, p.edges = c, nil, nil
+	if err != nil {
+		p.info.SetText(fmt.Sprintf("Посредничество недоступно: %v", err))
+	} else {
+		p.info.SetText("Число кратчайших путей между другими парами, проходящих через вершину или дугу")
+		for v := range c.Vertex {
+			p.verts = append(p.verts, v)
+		}
+		sort.SliceStable(p.verts, func(a, b int) bool { return c.Vertex[p.verts[a]] > c.Vertex[p.verts[b]] })
+		for i := 0; i < g.n; i++ {
+			for j := 0; j < g.n; j++ {
+				if g.HasEdge(i, j) {
+					p.edges = append(p.edges, [2]int{i, j})
+				}
+			}
+		}
+		sort.SliceStable(p.edges, func(a, b int) bool { return c.Edge[p.edges[a]] > c.Edge[p.edges[b]] })
+	}
+	p.vertexT.Refresh()
+	p.edgeT.Refresh()
+}
+
+// Scale returns the drawing scale for the canvas, nil when scaling is off.
+func (p *centralityPanel) Scale() *centralityScale {
+	if !p.scale.Checked || p.c.Vertex == nil {
+		return nil
+	}
+	return newCentralityScale(p.c)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestBetweenness(t *testing.T) {
+	cases := []struct {
+		name   string
+		rows   []string
+		vertex []float64
+		edge   map[[2]int]float64
+	}{
+		// s → a = 1, s → b = 1, b → a = 0, in both vertex orders so that
+		// either a or b is settled first
+		{"zero-weight tie, a first", []string{"0;1;1", ";0;", ";0;0"},
+			[]float64{0, 0, 0.5}, map[[2]int]float64{{0, 1}: 0.5, {0, 2}: 1.5, {2, 1}: 1.5}},
+		{"zero-weight tie, b first", []string{"0;1;1", ";0;0", ";;0"},
+			[]float64{0, 0.5, 0}, map[[2]int]float64{{0, 2}: 0.5, {0, 1}: 1.5, {1, 2}: 1.5}},
+		{"zero-weight chain", []string{"0;0;0", ";0;0", ";;0"},
+			[]float64{0, 0.5, 0}, map[[2]int]float64{{0, 1}: 1.5, {1, 2}: 1.5, {0, 2}: 0.5}},
+		{"plain ties", []string{"0;1;1;", ";0;;1", ";;0;1", ";;;0"},
+			[]float64{0, 0.5, 0.5, 0}, map[[2]int]float64{{0, 1}: 1.5, {0, 2}: 1.5, {1, 3}: 1.5, {2, 3}: 1.5}},
+		{"no ties", []string{"0;1;5", ";0;1", ";;0"},
+			[]float64{0, 1, 0}, map[[2]int]float64{{0, 1}: 2, {1, 2}: 2}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := betweenness(matrixGraph(t, c.rows...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.Vertex, c.vertex) {
+				t.Errorf("vertex betweenness %v, want %v", got.Vertex, c.vertex)
+			}
+			if !maps.Equal(got.Edge, c.edge) {
+				t.Errorf("edge betweenness %v, want %v", got.Edge, c.edge)
+			}
+		})
+	}
+}
+
+func TestBetweennessRejects(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		rows []string
+		want error
+	}{
+		{"zero-weight cycle", []string{"0;0;1", "0;0;", ";;0"}, errZeroCycle},
+		{"zero-weight cycle off the source", []string{"0;1;", ";0;0", ";0;0"}, errZeroCycle},
+		{"negative edge", []string{"0;-1", ";0"}, errNegativeEdges},
+	} {
+		if _, err := betweenness(matrixGraph(t, c.rows...)); err != c.want {
+			t.Errorf("%s: error %v, want %v", c.name, err, c.want)
+		}
+	}
+}
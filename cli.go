@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// ---------------- Command-line mode ----------------
+
+const cliUsage = `Использование:
+  apsp solve [--algo floyd|dijkstra|bellman-ford] [--in graph.csv] [--out result.csv]
+             [--format matrix|edges|dot|graphml|json] [--sep ';'] [--weights float|int|decimal|rat]
+             [--out-format csv|json|md|html] [--out-sep ';'] [--decimal-comma] [--bom]
+             [--paths shortest|widest|reliable|minimax]
+  apsp convert --in graph.dot --out graph.graphml [--from ФОРМАТ] [--to ФОРМАТ]
+  apsp gen [--type gnp|dag|grid|complete|geometric|negcycle] [--n 10] [--p 0.3]
+           [--seed 1] [--min 1] [--max 10] [--neg 0] [--int=true] [--out graph.csv] [--to ФОРМАТ]
+  apsp render --in graph.graphml --out graph.png|graph.svg [--width 1000] [--height 750]
+             [--format ФОРМАТ] [--path 1,4]
+  apsp serve [--addr 127.0.0.1:8080]
+
+HTTP-сервис (serve):
+  PUT  /graph                 {"n": 3, "edges": [{"from": 1, "to": 2, "weight": 1.5}]}
+                              или {"matrix": [[0, 1, null], ...]}
+  GET  /graph                 текущий граф
+  POST /solve?algo=floyd      матрица расстояний (paths=1 — ещё и пути)
+  GET  /path?from=1&to=3      длина и путь (по кэшу Флойда)
+
+Форматы графа: matrix, edges (CSV), dot, graphml, json (node-link).
+В convert формат по умолчанию определяется по расширению файла.
+
+Без аргументов запускается графический интерфейс.
+"-" или пустое значение в --in/--out означает stdin/stdout.
+`
+
+// runCLI executes a command-line subcommand and returns the process exit code.
+func runCLI(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "solve":
+		return cliSolve(args[1:], stdin, stdout, stderr)
+	case "convert":
+		return cliConvert(args[1:], stdin, stdout, stderr)
+	case "gen":
+		return cliGen(args[1:], stdout, stderr)
+	case "render":
+		return cliRender(args[1:], stdin, stdout, stderr)
+	case "serve":
+		return cliServe(args[1:], stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, cliUsage)
+		return 0
+	}
+	fmt.Fprintf(stderr, "apsp: неизвестная команда %q\n\n%s", args[0], cliUsage)
+	return 2
+}
+
+func cliSolve(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	algo := fs.String("algo", "floyd", "алгоритм: "+solverKeys())
+	inPath := fs.String("in", "-", "входной файл графа")
+	outPath := fs.String("out", "-", "файл результатов")
+	format := fs.String("format", "matrix", "формат входа: matrix, edges, dot, graphml или json")
+	sepFlag := fs.String("sep", ";", "разделитель полей во входном файле")
+	weights := fs.String("weights", "float", "арифметика весов: float, int, decimal или rat")
+	paths := fs.String("paths", "shortest", "вид путей: shortest, widest, reliable или minimax (не shortest — только floyd)")
+	outFormat := fs.String("out-format", "csv", "формат результата: csv, json, md или html")
+	outSep := fs.String("out-sep", ";", "разделитель полей в CSV-результате")
+	decimalComma := fs.Bool("decimal-comma", false, "десятичная запятая в числах (Excel, русская локаль)")
+	bom := fs.Bool("bom", false, "записать UTF-8 BOM в начало CSV")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if _, ok := exportFormatByKey(*outFormat); !ok {
+		fmt.Fprintf(stderr, "apsp: неизвестный формат результата %q\n", *outFormat)
+		return 2
+	}
+	sv, ok := solverByKey(*algo)
+	if !ok {
+		fmt.Fprintf(stderr, "apsp: %v\n", errUnknownSolver(*algo))
+		return 2
+	}
+	if _, ok := weightKindByKey(*weights); !ok {
+		fmt.Fprintf(stderr, "apsp: %v\n", errUnknownWeights(*weights))
+		return 2
+	}
+	if _, ok := semiringByKey(*paths); !ok {
+		fmt.Fprintf(stderr, "apsp: %v\n", errUnknownSemiring(*paths))
+		return 2
+	}
+	sep, size := utf8.DecodeRuneInString(*sepFlag)
+	if size == 0 || size != len(*sepFlag) {
+		fmt.Fprintf(stderr, "apsp: --sep должен быть одним символом\n")
+		return 2
+	}
+	outSepRune, size := utf8.DecodeRuneInString(*outSep)
+	if size == 0 || size != len(*outSep) {
+		fmt.Fprintf(stderr, "apsp: --out-sep должен быть одним символом\n")
+		return 2
+	}
+	opts := exportOptions{Format: *outFormat, Sep: outSepRune, DecimalComma: *decimalComma, BOM: *bom}
+
+	g, err := readGraphFile(*inPath, stdin, *format, sep)
+	if err != nil {
+		fmt.Fprintf(stderr, "apsp: чтение графа: %v\n", err)
+		return 1
+	}
+
+	res, err := runSolver(sv, *weights, *paths, g)
+	if err != nil {
+		fmt.Fprintf(stderr, "apsp: %s: %v\n", sv.Title, err)
+		return 1
+	}
+
+	if err := writeOutput(*outPath, stdout, func(w io.Writer) error { return writeResults(w, g, res, opts) }); err != nil {
+		fmt.Fprintf(stderr, "apsp: запись результата: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func cliConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	inPath := fs.String("in", "-", "входной файл графа")
+	outPath := fs.String("out", "-", "выходной файл графа")
+	from := fs.String("from", "", "формат входа (по умолчанию по расширению)")
+	to := fs.String("to", "", "формат выхода (по умолчанию по расширению)")
+	sepFlag := fs.String("sep", ";", "разделитель полей для CSV")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *from == "" {
+		*from = *inPath
+	}
+	if *to == "" {
+		*to = *outPath
+	}
+	outFormat, ok := graphFileFormat(*to)
+	if !ok {
+		fmt.Fprintf(stderr, "apsp: не удалось определить формат выхода, укажите --to\n")
+		return 2
+	}
+	sep, size := utf8.DecodeRuneInString(*sepFlag)
+	if size == 0 || size != len(*sepFlag) {
+		fmt.Fprintf(stderr, "apsp: --sep должен быть одним символом\n")
+		return 2
+	}
+	format, ok := graphFileFormat(*from)
+	if !ok {
+		fmt.Fprintf(stderr, "apsp: не удалось определить формат входа, укажите --from\n")
+		return 2
+	}
+	g, err := readGraphFile(*inPath, stdin, format, sep)
+	if err != nil {
+		fmt.Fprintf(stderr, "apsp: чтение графа: %v\n", err)
+		return 1
+	}
+	if err := writeOutput(*outPath, stdout, func(w io.Writer) error { return writeGraphFile(w, g, outFormat, sep) }); err != nil {
+		fmt.Fprintf(stderr, "apsp: запись графа: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func cliRender(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	inPath := fs.String("in", "-", "входной файл графа")
+	outPath := fs.String("out", "-", "файл изображения (.svg или .png)")
+	format := fs.String("format", "", "формат входа (по умолчанию по расширению)")
+	imgFormat := fs.String("image", "", "svg или png (по умолчанию по расширению --out)")
+	sepFlag := fs.String("sep", ";", "разделитель полей для CSV-входа")
+	width := fs.Int("width", 1000, "ширина в пикселях")
+	height := fs.Int("height", 750, "высота в пикселях")
+	pathFlag := fs.String("path", "", "выделить кратчайший путь: from,to (1-based)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *imgFormat == "" {
+		*imgFormat = *outPath
+	}
+	imgFmt, ok := imageFormatFor(*imgFormat)
+	if !ok {
+		fmt.Fprintf(stderr, "apsp: не удалось определить формат изображения, укажите --image svg|png\n")
+		return 2
+	}
+	var from, to int
+	if *pathFlag != "" {
+		if _, err := fmt.Sscanf(*pathFlag, "%d,%d", &from, &to); err != nil {
+			fmt.Fprintf(stderr, "apsp: --path ожидает from,to\n")
+			return 2
+		}
+	}
+	sep, size := utf8.DecodeRuneInString(*sepFlag)
+	if size == 0 || size != len(*sepFlag) {
+		fmt.Fprintf(stderr, "apsp: --sep должен быть одним символом\n")
+		return 2
+	}
+	if *format == "" {
+		*format = "matrix"
+		if f, ok := graphFormatFor(*inPath); ok {
+			*format = f.Key
+		}
+	}
+	g, err := readGraphFile(*inPath, stdin, *format, sep)
+	if err != nil {
+		fmt.Fprintf(stderr, "apsp: чтение графа: %v\n", err)
+		return 1
+	}
+
+	var path []int
+	if *pathFlag != "" {
+		if from < 1 || to < 1 || from > g.n || to > g.n {
+			fmt.Fprintf(stderr, "apsp: --path: вершины должны быть от 1 до %d\n", g.n)
+			return 2
+		}
+		res, err := solveFloyd(g, nil)
+		if err != nil {
+			fmt.Fprintf(stderr, "apsp: %v\n", err)
+			return 1
+		}
+		if path = res.Path(from-1, to-1); path == nil {
+			fmt.Fprintf(stderr, "apsp: из %d в %d пути нет\n", from, to)
+		}
+	}
+	sc := sceneFromGraph(g, path)
+	if *pathFlag != "" {
+		sc.Start, sc.End = from-1, to-1
+	}
+	if err := writeOutput(*outPath, stdout, func(w io.Writer) error { return writeSceneImage(w, sc, imgFmt, *width, *height) }); err != nil {
+		fmt.Fprintf(stderr, "apsp: запись изображения: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func cliGen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	kind := fs.String("type", "gnp", "генератор: gnp, dag, grid, complete, geometric или negcycle")
+	var p genParams
+	fs.IntVar(&p.N, "n", 10, "число вершин")
+	fs.Float64Var(&p.P, "p", 0.3, "вероятность дуги (для geometric — радиус связи в единичном квадрате)")
+	fs.Uint64Var(&p.Seed, "seed", 1, "зерно генератора случайных чисел")
+	fs.Float64Var(&p.MinW, "min", 1, "минимальный вес")
+	fs.Float64Var(&p.MaxW, "max", 10, "максимальный вес")
+	fs.Float64Var(&p.NegProb, "neg", 0, "вероятность отрицательного веса")
+	fs.BoolVar(&p.Integer, "int", true, "целые веса")
+	outPath := fs.String("out", "-", "выходной файл графа")
+	to := fs.String("to", "", "формат выхода: matrix, edges, dot, graphml или json (по умолчанию по расширению, иначе matrix)")
+	sepFlag := fs.String("sep", ";", "разделитель полей для CSV")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *to == "" {
+		*to = "matrix"
+		if f, ok := graphFormatFor(*outPath); ok {
+			*to = f.Key
+		}
+	}
+	format, ok := graphFileFormat(*to)
+	if !ok {
+		fmt.Fprintf(stderr, "apsp: неизвестный формат выхода %q\n", *to)
+		return 2
+	}
+	sep, size := utf8.DecodeRuneInString(*sepFlag)
+	if size == 0 || size != len(*sepFlag) {
+		fmt.Fprintf(stderr, "apsp: --sep должен быть одним символом\n")
+		return 2
+	}
+	g, err := generateGraph(*kind, p)
+	if err != nil {
+		fmt.Fprintf(stderr, "apsp: %v\n", err)
+		return 2
+	}
+	if err := writeOutput(*outPath, stdout, func(w io.Writer) error { return writeGraphFile(w, g, format, sep) }); err != nil {
+		fmt.Fprintf(stderr, "apsp: запись графа: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// graphFileFormat resolves a format key or a file name to a graph format key,
+// CSV layouts (matrix, edges) included.
+func graphFileFormat(name string) (string, bool) {
+	if name == "matrix" || name == "edges" {
+		return name, true
+	}
+	if f, ok := graphFormatFor(name); ok {
+		return f.Key, true
+	}
+	return "", false
+}
+
+func writeGraphFile(w io.Writer, g *Graph, format string, sep rune) error {
+	if f, ok := graphFormatFor(format); ok && f.Key == format {
+		return f.Write(w, g)
+	}
+	return writeGraphCSV(w, g, format, sep)
+}
+
+// readGraphFile reads a graph in any supported format from the named file,
+// or stdin for "" and "-".
+func readGraphFile(path string, stdin io.Reader, format string, sep rune) (*Graph, error) {
+	in := stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	if f, ok := graphFormatFor(format); ok && f.Key == format {
+		return f.Read(in)
+	}
+	return readGraphCSV(in, format, sep)
+}
+
+// writeOutput runs write against the named file, or stdout for "" and "-".
+func writeOutput(path string, stdout io.Writer, write func(io.Writer) error) error {
+	if path == "" || path == "-" {
+		bw := bufio.NewWriter(stdout)
+		if err := write(bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return errors.Join(write(f), f.Close())
+}
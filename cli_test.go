@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type cliCase struct {
+	name   string
+	args   []string
+	stdin  string
+	code   int
+	stdout string // substring expected in stdout
+	stderr string // substring expected in stderr
+}
+
+func runCLICases(t *testing.T, cases []cliCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := runCLI(c.args, strings.NewReader(c.stdin), &out, &errOut)
+			if code != c.code {
+				t.Fatalf("exit code %d, want %d\nstdout: %s\nstderr: %s", code, c.code, out.String(), errOut.String())
+			}
+			if !strings.Contains(out.String(), c.stdout) {
+				t.Errorf("stdout %q does not contain %q", out.String(), c.stdout)
+			}
+			if !strings.Contains(errOut.String(), c.stderr) {
+				t.Errorf("stderr %q does not contain %q", errOut.String(), c.stderr)
+			}
+		})
+	}
+}
+
+const cliTriangle = "0;1;5\n;0;1\n;;0\n"
+
+func TestCLISolve(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.csv")
+	runCLICases(t, []cliCase{
+		{name: "help", args: []string{"help"}, stdout: "apsp solve"},
+		{name: "unknown command", args: []string{"frobnicate"}, code: 2, stderr: "неизвестная команда"},
+		{name: "matrix from stdin", args: []string{"solve"}, stdin: cliTriangle,
+			stdout: "i;j;length;path\n1;2;1;1 2\n1;3;2;1 2 3\n"},
+		{name: "unreachable pair", args: []string{"solve", "--algo", "dijkstra"}, stdin: cliTriangle, stdout: "3;1;inf;\n"},
+		{name: "edge list", args: []string{"solve", "--format", "edges", "--sep", ","}, stdin: "u,v,w\n1,2,1\n2,3,1\n1,3,5\n",
+			stdout: "1;3;2;1 2 3\n"},
+		{name: "unknown flag", args: []string{"solve", "--nope"}, code: 2, stderr: "nope"},
+		{name: "unknown algorithm", args: []string{"solve", "--algo", "a-star"}, code: 2, stderr: "неизвестный алгоритм"},
+		{name: "long separator", args: []string{"solve", "--sep", ";;"}, code: 2, stderr: "одним символом"},
+		{name: "unknown format", args: []string{"solve", "--format", "xml"}, stdin: cliTriangle, code: 1, stderr: "неизвестный формат"},
+		{name: "missing input file", args: []string{"solve", "--in", missing}, code: 1, stderr: "missing.csv"},
+		{name: "ragged matrix", args: []string{"solve"}, stdin: "0;1\n0\n", code: 1, stderr: "строка 2"},
+		{name: "bad weight", args: []string{"solve", "--format", "edges"}, stdin: "1;2;x\n", code: 1, stderr: "некорректное число"},
+		{name: "huge vertex id", args: []string{"solve", "--format", "edges"}, stdin: "999999999;1;1\n", code: 1, stderr: "больше 2000"},
+		{name: "duplicate edge", args: []string{"solve", "--format", "edges"}, stdin: "1;2;1\n1;2;3\n", code: 1, stderr: "повторяется"},
+		{name: "negative cycle", args: []string{"solve"}, stdin: "0;-1\n-1;0\n", code: 1, stderr: "цикл"},
+	})
+}
+
+func TestCLISolveWritesFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "res.csv")
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"solve", "--out", out}, strings.NewReader(cliTriangle), &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "1;3;2;1 2 3") || stdout.Len() != 0 {
+		t.Fatalf("file %q, stdout %q", data, stdout.String())
+	}
+}
+
+func TestCLIServe(t *testing.T) {
+	runCLICases(t, []cliCase{
+		{name: "unknown flag", args: []string{"serve", "--port", "80"}, code: 2, stderr: "port"},
+		{name: "bad address", args: []string{"serve", "--addr", "127.0.0.1:-1"}, code: 1, stderr: "apsp:"},
+	})
+}
+
+func TestCLIConvert(t *testing.T) {
+	runCLICases(t, []cliCase{
+		{name: "matrix to dot", args: []string{"convert", "--from", "matrix", "--to", "dot"}, stdin: cliTriangle,
+			stdout: `1 -> 3 [w="5", label="5"];`},
+		{name: "edges to json", args: []string{"convert", "--from", "edges", "--to", "json"}, stdin: "1;2;-1\n",
+			stdout: `"weight": -1`},
+		{name: "dot to graphml", args: []string{"convert", "--from", "dot", "--to", "graphml"}, stdin: "digraph { a -> b [w=2]; }",
+			stdout: "<graphml"},
+		{name: "unknown flag", args: []string{"convert", "--format", "dot"}, code: 2, stderr: "format"},
+		{name: "no output format", args: []string{"convert", "--from", "dot"}, code: 2, stderr: "укажите --to"},
+		{name: "no input format", args: []string{"convert", "--to", "dot"}, code: 2, stderr: "укажите --from"},
+		{name: "long separator", args: []string{"convert", "--from", "edges", "--to", "dot", "--sep", "ab"}, code: 2, stderr: "одним символом"},
+		{name: "bad input", args: []string{"convert", "--from", "json", "--to", "dot"}, stdin: `{"nodes": [`, code: 1, stderr: "чтение графа"},
+		{name: "duplicate edge", args: []string{"convert", "--from", "dot", "--to", "json"}, stdin: "digraph { a -> b; a -> b; }",
+			code: 1, stderr: "повторяется"},
+	})
+}
+
+func TestCLIRender(t *testing.T) {
+	runCLICases(t, []cliCase{
+		{name: "svg to stdout", args: []string{"render", "--image", "svg", "--path", "1,3"}, stdin: cliTriangle, stdout: "<svg"},
+		{name: "unknown flag", args: []string{"render", "--dpi", "300"}, code: 2, stderr: "dpi"},
+		{name: "no image format", args: []string{"render"}, stdin: cliTriangle, code: 2, stderr: "укажите --image"},
+		{name: "bad path flag", args: []string{"render", "--image", "svg", "--path", "1-3"}, code: 2, stderr: "from,to"},
+		{name: "path out of range", args: []string{"render", "--image", "svg", "--path", "1,9"}, stdin: cliTriangle, code: 2, stderr: "от 1 до 3"},
+		{name: "no path", args: []string{"render", "--image", "svg", "--path", "3,1"}, stdin: cliTriangle, stdout: "<svg", stderr: "пути нет"},
+		{name: "too small", args: []string{"render", "--image", "png", "--width", "8"}, stdin: cliTriangle, code: 1, stderr: "размер изображения"},
+		{name: "bad input", args: []string{"render", "--image", "svg"}, stdin: "0;x\n", code: 1, stderr: "чтение графа"},
+	})
+}
+
+func TestCLIGen(t *testing.T) {
+	runCLICases(t, []cliCase{
+		{name: "complete matrix", args: []string{"gen", "--type", "complete", "--n", "2", "--min", "3", "--max", "3"}, stdout: "0;3\n3;0\n"},
+		{name: "edges", args: []string{"gen", "--type", "dag", "--n", "2", "--p", "1", "--min", "4", "--max", "4", "--to", "edges"}, stdout: ";4\n"},
+		{name: "dot", args: []string{"gen", "--to", "dot", "--n", "3"}, stdout: "digraph"},
+		{name: "unknown flag", args: []string{"gen", "--vertices", "3"}, code: 2, stderr: "vertices"},
+		{name: "bad number", args: []string{"gen", "--n", "three"}, code: 2, stderr: "invalid value"},
+		{name: "unknown generator", args: []string{"gen", "--type", "tree"}, code: 2, stderr: "неизвестный генератор"},
+		{name: "unknown output format", args: []string{"gen", "--to", "xml"}, code: 2, stderr: "неизвестный формат выхода"},
+		{name: "long separator", args: []string{"gen", "--sep", "::"}, code: 2, stderr: "одним символом"},
+		{name: "bad probability", args: []string{"gen", "--p", "2"}, code: 2, stderr: "вероятность дуги"},
+		{name: "min above max", args: []string{"gen", "--min", "5", "--max", "1"}, code: 2, stderr: "минимальный вес"},
+	})
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Solver comparison ----------------
+
+// compareEps is the relative tolerance for distances computed by different solvers.
+const compareEps = 1e-9
+
+type solverRun struct {
+	Solver  apspSolver
+	Stats   SolverStats
+	Elapsed time.Duration
+	Res     *APSPResult
+	Err     error
+}
+
+func runAllSolvers(g *Graph) []solverRun {
+	runs := make([]solverRun, 0, len(apspSolvers))
+	for _, sv := range apspSolvers {
+		r := solverRun{Solver: sv}
+		start := time.Now()
+		res, err := sv.Run(g, &r.Stats)
+		r.Elapsed = time.Since(start)
+		r.Err = err
+		if err == nil {
+			r.Res = res
+		}
+		runs = append(runs, r)
+	}
+	return runs
+}
+
+// sameDist compares two distances, each with its reachability flag.
+func sameDist(a float64, aok bool, b float64, bok bool) bool {
+	if !aok || !bok {
+		return aok == bok
+	}
+	return math.Abs(a-b) <= compareEps*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// mismatchedPairs returns pairs whose distance differs between successful runs.
+func mismatchedPairs(runs []solverRun, n int) map[[2]int]bool {
+	bad := make(map[[2]int]bool)
+	var ref *APSPResult
+	for _, r := range runs {
+		if r.Err != nil {
+			continue
+		}
+		if ref == nil {
+			ref = r.Res
+			continue
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a, aok := ref.Dist(i, j)
+				b, bok := r.Res.Dist(i, j)
+				if !sameDist(a, aok, b, bok) {
+					bad[[2]int{i, j}] = true
+				}
+			}
+		}
+	}
+	return bad
+}
+
+// distText formats a distance, "∞" when the target is unreachable.
+func distText(d float64, ok bool) string {
+	switch {
+	case !ok || math.IsInf(d, 1):
+		// an infinite value is the empty path of widest paths
+		return "∞"
+	case math.IsInf(d, -1):
+		return "-∞"
+	}
+	return strconv.FormatFloat(d, 'g', -1, 64)
+}
+
+func showSolverComparison(a fyne.App, g *Graph) {
+	n := g.n
+	runs := runAllSolvers(g)
+	bad := mismatchedPairs(runs, n)
+
+	w := a.NewWindow("Сравнение алгоритмов")
+	w.Resize(fyne.NewSize(1000, 560))
+
+	summary := widget.NewTable(
+		func() (int, int) { return len(runs) + 1, 8 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			lbl := co.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.SetText([]string{"Алгоритм", "Время", "Итераций", "Релаксаций", "Успешных", "Сравнений", "Куча", "Статус"}[id.Col])
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+			r := runs[id.Row-1]
+			switch id.Col {
+			case 0:
+				lbl.SetText(r.Solver.Title)
+			case 1:
+				lbl.SetText(r.Elapsed.String())
+			case 2:
+				lbl.SetText(strconv.FormatInt(r.Stats.Iterations, 10))
+			case 3:
+				lbl.SetText(strconv.FormatInt(r.Stats.Relaxations, 10))
+			case 4:
+				lbl.SetText(strconv.FormatInt(r.Stats.Improved, 10))
+			case 5:
+				lbl.SetText(strconv.FormatInt(r.Stats.Comparisons, 10))
+			case 6:
+				lbl.SetText(strconv.FormatInt(r.Stats.HeapOps, 10))
+			case 7:
+				if r.Err != nil {
+					lbl.SetText(r.Err.Error())
+				} else {
+					lbl.SetText("ok")
+				}
+			}
+		},
+	)
+	summary.SetColumnWidth(0, 150)
+	summary.SetColumnWidth(1, 100)
+	for c := 2; c <= 6; c++ {
+		summary.SetColumnWidth(c, 96)
+	}
+	summary.SetColumnWidth(7, 320)
+
+	type pairRow struct{ i, j int }
+	var pairs []pairRow
+	onlyBad := false
+	rebuild := func() {
+		pairs = pairs[:0]
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if i != j && (!onlyBad || bad[[2]int{i, j}]) {
+					pairs = append(pairs, pairRow{i, j})
+				}
+			}
+		}
+	}
+	rebuild()
+
+	cols := 2 + len(runs) + 1
+	side := widget.NewTable(
+		func() (int, int) { return len(pairs) + 1, cols },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			lbl := co.(*widget.Label)
+			if id.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				switch {
+				case id.Col == 0:
+					lbl.SetText("i")
+				case id.Col == 1:
+					lbl.SetText("j")
+				case id.Col == cols-1:
+					lbl.SetText("Совпадает")
+				default:
+					lbl.SetText(runs[id.Col-2].Solver.Title)
+				}
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+			p := pairs[id.Row-1]
+			switch {
+			case id.Col == 0:
+				lbl.SetText(vertexName(p.i))
+			case id.Col == 1:
+				lbl.SetText(vertexName(p.j))
+			case id.Col == cols-1:
+				if bad[[2]int{p.i, p.j}] {
+					lbl.SetText("≠ расхождение")
+				} else {
+					lbl.SetText("да")
+				}
+			default:
+				r := runs[id.Col-2]
+				if r.Err != nil {
+					lbl.SetText("—")
+				} else {
+					lbl.SetText(r.Res.DistText(p.i, p.j))
+				}
+			}
+		},
+	)
+	side.SetColumnWidth(0, 40)
+	side.SetColumnWidth(1, 40)
+	for k := range runs {
+		side.SetColumnWidth(2+k, 140)
+	}
+	side.SetColumnWidth(cols-1, 140)
+
+	verdict := widget.NewLabel(fmt.Sprintf("Расхождений: %d (допуск %g)", len(bad), compareEps))
+	onlyBadCheck := widget.NewCheck("только расхождения", func(b bool) {
+		onlyBad = b
+		rebuild()
+		side.Refresh()
+	})
+
+	top := container.NewBorder(nil, nil, nil, nil, summary)
+	bottom := container.NewBorder(container.NewHBox(verdict, onlyBadCheck), nil, nil, nil, side)
+	split := container.NewVSplit(top, bottom)
+	split.Offset = 0.3
+	w.SetContent(split)
+	w.Show()
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Strongly connected components ----------------
+
+// SCC labels every vertex with its strongly connected component (Tarjan).
+// Components are numbered in topological order of the condensation: an edge
+// between different components always goes from a smaller number to a larger.
+func (g *Graph) SCC() (comp []int, count int) {
+	n := g.n
+	index := make([]int, n) // discovery order + 1, 0 = not visited yet
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	comp = make([]int, n)
+	var stack []int
+	next := 0
+	var found [][]int // in reverse topological order
+
+	var visit func(v int)
+	visit = func(v int) {
+		next++
+		index[v], low[v] = next, next
+		stack = append(stack, v)
+		onStack[v] = true
+		for u := 0; u < n; u++ {
+			if !g.HasEdge(v, u) {
+				continue
+			}
+			if index[u] == 0 {
+				visit(u)
+				low[v] = min(low[v], low[u])
+			} else if onStack[u] {
+				low[v] = min(low[v], index[u])
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		var members []int
+		for {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[u] = false
+			members = append(members, u)
+			if u == v {
+				break
+			}
+		}
+		found = append(found, members)
+	}
+	for v := 0; v < n; v++ {
+		if index[v] == 0 {
+			visit(v)
+		}
+	}
+	count = len(found)
+	for k, members := range found {
+		for _, v := range members {
+			comp[v] = count - 1 - k
+		}
+	}
+	return comp, count
+}
+
+// componentMembers lists the vertices of every component in increasing order.
+func componentMembers(comp []int, count int) [][]int {
+	out := make([][]int, count)
+	for v, c := range comp {
+		out[c] = append(out[c], v)
+	}
+	return out
+}
+
+// TransitiveClosure is Warshall's algorithm: reach[i][j] reports a path from
+// i to j. Every vertex reaches itself.
+func (g *Graph) TransitiveClosure() Matrix[bool] {
+	reach := g.has.Clone()
+	for i := 0; i < g.n; i++ {
+		reach.Set(i, i, true)
+	}
+	for k := 0; k < g.n; k++ {
+		rk := reach.Row(k)
+		for i := 0; i < g.n; i++ {
+			if !reach.At(i, k) {
+				continue
+			}
+			ri := reach.Row(i)
+			for j, ok := range rk {
+				ri[j] = ri[j] || ok
+			}
+		}
+	}
+	return reach
+}
+
+// Condensation contracts every component of g into one vertex labelled with
+// its members. An edge between two components keeps the smallest weight of
+// the edges it replaces. Vertices are laid out in layers by longest path from
+// the sources, so edges point downwards.
+func (g *Graph) Condensation() *Graph {
+	comp, count := g.SCC()
+	c := NewGraph()
+	c.Resize(count)
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			w, ok := g.Edge(i, j)
+			if !ok || comp[i] == comp[j] {
+				continue
+			}
+			if cw, has := c.Edge(comp[i], comp[j]); !has || w < cw {
+				c.SetEdge(comp[i], comp[j], w, false)
+			}
+		}
+	}
+	for k, members := range componentMembers(comp, count) {
+		names := make([]string, len(members))
+		for t, v := range members {
+			names[t] = g.Label(v)
+		}
+		c.SetLabel(k, "{"+strings.Join(names, ", ")+"}")
+	}
+
+	// components are topologically ordered, so one pass gives the layers
+	layer := make([]int, count)
+	for u := 0; u < count; u++ {
+		for v := u + 1; v < count; v++ {
+			if c.HasEdge(u, v) {
+				layer[v] = max(layer[v], layer[u]+1)
+			}
+		}
+	}
+	width := make(map[int]int)
+	for v := 0; v < count; v++ {
+		c.SetPos(v, Point{X: 60 + float64(width[layer[v]])*120, Y: 50 + float64(layer[v])*90})
+		width[layer[v]]++
+	}
+	return c
+}
+
+// componentPalette colours components on the canvas; it repeats after ten.
+var componentPalette = []color.NRGBA{
+	{R: 191, G: 219, B: 254, A: 255},
+	{R: 187, G: 247, B: 208, A: 255},
+	{R: 254, G: 215, B: 170, A: 255},
+	{R: 233, G: 213, B: 255, A: 255},
+	{R: 254, G: 240, B: 138, A: 255},
+	{R: 251, G: 207, B: 232, A: 255},
+	{R: 153, G: 246, B: 228, A: 255},
+	{R: 254, G: 202, B: 202, A: 255},
+	{R: 199, G: 210, B: 254, A: 255},
+	{R: 217, G: 249, B: 157, A: 255},
+}
+
+func componentColor(c int) color.NRGBA { return componentPalette[c%len(componentPalette)] }
+
+// componentsPanel shows the components, the reachability matrix and the
+// condensation DAG of a graph.
+type componentsPanel struct {
+	box     fyne.CanvasObject
+	summary *widget.Label
+	reach   *widget.Table
+	dag     *canvas.Image
+
+	closure Matrix[bool]
+}
+
+func newComponentsPanel() *componentsPanel {
+	p := &componentsPanel{summary: widget.NewLabel("")}
+	p.summary.Wrapping = fyne.TextWrapWord
+	p.reach = widget.NewTable(
+		func() (int, int) { return p.closure.N(), p.closure.N() },
+		func() fyne.CanvasObject { return widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{}) },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			txt := ""
+			if id.Row < p.closure.N() && id.Col < p.closure.N() && p.closure.At(id.Row, id.Col) {
+				txt = "1"
+			}
+			co.(*widget.Label).SetText(txt)
+		},
+	)
+	p.reach.ShowHeaderRow = true
+	p.reach.ShowHeaderColumn = true
+	p.reach.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	}
+	p.reach.UpdateHeader = func(id widget.TableCellID, co fyne.CanvasObject) {
+		lbl := co.(*widget.Label)
+		switch {
+		case id.Row < 0 && id.Col < 0:
+			lbl.SetText("i/j")
+		case id.Row < 0:
+			lbl.SetText(vertexName(id.Col))
+		default:
+			lbl.SetText(vertexName(id.Row))
+		}
+	}
+	p.dag = canvas.NewImageFromImage(nil)
+	p.dag.FillMode = canvas.ImageFillContain
+	p.dag.SetMinSize(fyne.NewSize(300, 200))
+
+	tables := container.NewVSplit(
+		container.NewBorder(widget.NewLabelWithStyle("Достижимость (транзитивное замыкание)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), nil, nil, nil, p.reach),
+		container.NewBorder(widget.NewLabelWithStyle("Граф конденсации", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), nil, nil, nil, p.dag),
+	)
+	p.box = container.NewBorder(p.summary, nil, nil, nil, tables)
+	return p
+}
+
+// Update recomputes everything for g.
+func (p *componentsPanel) Update(g *Graph) {
+	comp, count := g.SCC()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Компонент сильной связности: %d", count)
+	for k, members := range componentMembers(comp, count) {
+		names := make([]string, len(members))
+		for t, v := range members {
+			names[t] = vertexName(v)
+		}
+		fmt.Fprintf(&b, "\nК%d: %s", k+1, strings.Join(names, ", "))
+	}
+	p.summary.SetText(b.String())
+
+	p.closure = g.TransitiveClosure()
+	for j := 0; j < g.n; j++ {
+		p.reach.SetColumnWidth(j, 36)
+	}
+	p.reach.Refresh()
+
+	dag := g.Condensation()
+	sc := sceneFromGraph(dag, nil)
+	sc.Comp = make([]int, dag.n)
+	for k := range sc.Comp {
+		sc.Comp[k] = k
+	}
+	img, err := renderScene(sc, 600, 400)
+	if err != nil {
+		p.dag.Image = nil
+	} else {
+		p.dag.Image = img
+	}
+	p.dag.Refresh()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkSCC compares Tarjan's components with the Warshall closure: u and v
+// share a component iff each reaches the other, and every edge between
+// components goes forward in their numbering.
+func checkSCC(t *testing.T, g *Graph, want int) {
+	t.Helper()
+	comp, count := g.SCC()
+	reach := g.TransitiveClosure()
+	if want >= 0 && count != want {
+		t.Fatalf("count = %d, want %d (comp %v)", count, want, comp)
+	}
+	seen := make(map[int]bool)
+	for u := 0; u < g.n; u++ {
+		if comp[u] < 0 || comp[u] >= count {
+			t.Fatalf("comp[%d] = %d out of [0, %d)", u, comp[u], count)
+		}
+		seen[comp[u]] = true
+		for v := 0; v < g.n; v++ {
+			mutual := reach.At(u, v) && reach.At(v, u)
+			if (comp[u] == comp[v]) != mutual {
+				t.Fatalf("%d, %d: comp %d/%d, closure %v/%v", u, v, comp[u], comp[v], reach.At(u, v), reach.At(v, u))
+			}
+			if g.HasEdge(u, v) && comp[u] > comp[v] {
+				t.Fatalf("edge %d → %d goes back: comp %d → %d", u, v, comp[u], comp[v])
+			}
+		}
+	}
+	if len(seen) != count {
+		t.Fatalf("%d components used, count = %d", len(seen), count)
+	}
+}
+
+func TestSCCMatchesClosure(t *testing.T) {
+	cases := []struct {
+		name  string
+		n     int
+		edges []testEdge
+		count int
+	}{
+		{"empty", 0, nil, 0},
+		{"single", 1, nil, 1},
+		{"loop", 1, []testEdge{{0, 0, 1}}, 1},
+		{"chain", 4, []testEdge{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}}, 4},
+		{"cycle", 3, []testEdge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}}, 1},
+		{"two cycles", 5, []testEdge{{0, 1, 1}, {1, 0, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 2, 1}}, 2},
+		{"isolated", 4, []testEdge{{3, 0, 1}, {0, 3, 1}}, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkSCC(t, edgesGraph(c.n, c.edges), c.count)
+		})
+	}
+
+	rng := rand.New(rand.NewSource(44))
+	for round := 0; round < 50; round++ {
+		g := randomGraph(rng, 1+rng.Intn(12), 0.15, func() float64 { return 1 })
+		checkSCC(t, g, -1)
+	}
+}
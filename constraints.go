@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// ---------------- Path constraints ----------------
+
+// vertexMark is a path-finder constraint set on a vertex by right-click.
+type vertexMark uint8
+
+const (
+	markNone    vertexMark = iota
+	markVia                // the path must visit the vertex
+	markBlocked            // the path must avoid the vertex
+)
+
+// next cycles none → via → blocked → none.
+func (m vertexMark) next() vertexMark { return (m + 1) % 3 }
+
+var (
+	viaColor     = color.NRGBA{R: 147, G: 51, B: 234, A: 255}
+	blockedFill  = color.NRGBA{R: 209, G: 213, B: 219, A: 255}
+	blockedColor = color.NRGBA{R: 252, G: 165, B: 165, A: 255}
+)
+
+// style adjusts the look of a marked vertex: waypoints get a thick purple
+// outline, blocked vertices are greyed out.
+func (m vertexMark) style(fill, stroke color.NRGBA, width float64) (color.NRGBA, color.NRGBA, float64) {
+	switch m {
+	case markVia:
+		return fill, viaColor, 4
+	case markBlocked:
+		return blockedFill, stroke, width
+	}
+	return fill, stroke, width
+}
+
+// pathConstraints asks for a path visiting every Via vertex (in any order)
+// and avoiding the blocked vertices and edges.
+type pathConstraints struct {
+	Via          []int
+	BlockedVerts []int
+	BlockedEdges [][2]int
+}
+
+func (c pathConstraints) empty() bool {
+	return len(c.Via)+len(c.BlockedVerts)+len(c.BlockedEdges) == 0
+}
+
+func (c pathConstraints) String() string {
+	var parts []string
+	if len(c.Via) > 0 {
+		parts = append(parts, "через "+vertexList(c.Via))
+	}
+	if len(c.BlockedVerts) > 0 {
+		parts = append(parts, "без вершин "+vertexList(c.BlockedVerts))
+	}
+	for _, e := range c.BlockedEdges {
+		parts = append(parts, fmt.Sprintf("без дуги %s → %s", vertexName(e[0]), vertexName(e[1])))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var errNoConstrainedPath = errors.New("Пути с такими ограничениями нет")
+
+// constrainedPath finds the shortest s → t walk through all waypoints that
+// avoids the blocked elements. Floyd–Warshall runs once on g without the
+// blocked elements; the walk joins shortest paths between consecutive
+// waypoints, trying every waypoint order. Segments may share vertices.
+func constrainedPath(g *Graph, s, t int, c pathConstraints) ([]int, float64, error) {
+	h := NewGraph()
+	h.Resize(g.n)
+	blocked := make([]bool, g.n)
+	for _, v := range c.BlockedVerts {
+		if v == s || v == t {
+			return nil, 0, fmt.Errorf("вершина %s запрещена, но является концом пути", vertexName(v))
+		}
+		blocked[v] = true
+	}
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if w, ok := g.Edge(i, j); ok && !blocked[i] && !blocked[j] {
+				h.SetEdge(i, j, w, false)
+			}
+		}
+	}
+	for _, e := range c.BlockedEdges {
+		h.SetEdge(e[0], e[1], 0, true)
+	}
+	res, err := solveFloyd(h, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var via []int
+	for _, v := range c.Via {
+		if v != s && v != t {
+			via = append(via, v)
+		}
+	}
+	best, bestLen := []int(nil), math.Inf(1)
+	order := make([]int, 0, len(via)+2)
+	used := make([]bool, len(via))
+	var try func(at int, length float64)
+	try = func(at int, length float64) {
+		if len(order) == len(via) {
+			d, ok := res.Dist(at, t)
+			if ok && length+d < bestLen {
+				bestLen = length + d
+				best = append(append(best[:0], order...), t)
+			}
+			return
+		}
+		for k, v := range via {
+			if used[k] {
+				continue
+			}
+			d, ok := res.Dist(at, v)
+			if !ok {
+				continue
+			}
+			used[k] = true
+			order = append(order, v)
+			try(v, length+d)
+			order = order[:len(order)-1]
+			used[k] = false
+		}
+	}
+	try(s, 0)
+	if best == nil {
+		return nil, 0, errNoConstrainedPath
+	}
+	path := []int{s + 1}
+	at := s
+	for _, v := range best {
+		seg := res.Path(at, v)
+		path = append(path, seg[1:]...)
+		at = v
+	}
+	return path, bestLen, nil
+}
+
+// TappedSecondary marks the vertex or edge under the pointer: vertices cycle
+// through waypoint, blocked and unmarked, edges toggle blocked.
+func (gc *GraphCanvas) TappedSecondary(ev *fyne.PointEvent) {
+	if vid := gc.findVertex(ev.Position); vid != -1 {
+		if m := gc.marks[vid].next(); m == markNone {
+			delete(gc.marks, vid)
+		} else {
+			gc.marks[vid] = m
+		}
+	} else if eidx := gc.findEdge(ev.Position); eidx != -1 {
+		k := [2]int{gc.edges[eidx].U, gc.edges[eidx].V}
+		if gc.blocked[k] {
+			delete(gc.blocked, k)
+		} else {
+			gc.blocked[k] = true
+		}
+	} else {
+		return
+	}
+	gc.Refresh()
+	if gc.onMarks != nil {
+		gc.onMarks()
+	}
+}
+
+// constraints collects the marks for constrainedPath in vertex order.
+func (gc *GraphCanvas) constraints() pathConstraints {
+	var c pathConstraints
+	for v, m := range gc.marks {
+		switch m {
+		case markVia:
+			c.Via = append(c.Via, v)
+		case markBlocked:
+			c.BlockedVerts = append(c.BlockedVerts, v)
+		}
+	}
+	sort.Ints(c.Via)
+	sort.Ints(c.BlockedVerts)
+	for _, e := range gc.edges {
+		if gc.blocked[[2]int{e.U, e.V}] {
+			c.BlockedEdges = append(c.BlockedEdges, [2]int{e.U, e.V})
+		}
+	}
+	return c
+}
+
+func (gc *GraphCanvas) clearConstraints() {
+	gc.marks = make(map[int]vertexMark)
+	gc.blocked = make(map[[2]int]bool)
+	gc.Refresh()
+}
+
+// dropVertexMarks forgets the marks of vertex vid and renumbers the ones
+// after it, following deleteVertex.
+func (gc *GraphCanvas) dropVertexMarks(vid int) {
+	marks := make(map[int]vertexMark, len(gc.marks))
+	for v, m := range gc.marks {
+		switch {
+		case v < vid:
+			marks[v] = m
+		case v > vid:
+			marks[v-1] = m
+		}
+	}
+	blocked := make(map[[2]int]bool, len(gc.blocked))
+	for e := range gc.blocked {
+		if e[0] == vid || e[1] == vid {
+			continue
+		}
+		for k := range e {
+			if e[k] > vid {
+				e[k]--
+			}
+		}
+		blocked[e] = true
+	}
+	gc.marks, gc.blocked = marks, blocked
+}
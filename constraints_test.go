@@ -0,0 +1,60 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConstrainedPath(t *testing.T) {
+	// 1 → 3 → 2 → 5 is short, 1 → 2 → 3 → 5 long; 1 → 2 → 4 is the direct
+	// way to 4, 1 → 3 → 4 the detour
+	g := matrixGraph(t,
+		"0;1;1;;",
+		";0;10;1;1",
+		";1;0;5;10",
+		";;;0;",
+		";;;;0",
+	)
+	cases := []struct {
+		name   string
+		s, t   int
+		c      pathConstraints
+		path   []int
+		length float64
+		err    error
+	}{
+		{"no constraints", 0, 4, pathConstraints{}, []int{1, 2, 5}, 2, nil},
+		{"two waypoints, reverse order is shorter", 0, 4, pathConstraints{Via: []int{1, 2}}, []int{1, 3, 2, 5}, 3, nil},
+		{"waypoints given in the other order", 0, 4, pathConstraints{Via: []int{2, 1}}, []int{1, 3, 2, 5}, 3, nil},
+		{"blocked vertex forces a detour", 0, 3, pathConstraints{BlockedVerts: []int{1}}, []int{1, 3, 4}, 6, nil},
+		{"blocked edge forces a detour", 0, 3, pathConstraints{BlockedEdges: [][2]int{{1, 3}}}, []int{1, 3, 4}, 6, nil},
+		{"blocked edge, waypoint still reachable", 0, 4, pathConstraints{Via: []int{2}, BlockedEdges: [][2]int{{0, 2}}}, []int{1, 2, 3, 2, 5}, 13, nil},
+		{"unreachable waypoint", 0, 4, pathConstraints{Via: []int{3}}, nil, 0, errNoConstrainedPath},
+		{"waypoint cut off by a block", 0, 4, pathConstraints{Via: []int{2}, BlockedVerts: []int{1}, BlockedEdges: [][2]int{{0, 2}}}, nil, 0, errNoConstrainedPath},
+		{"target cut off", 0, 3, pathConstraints{BlockedVerts: []int{1, 2}}, nil, 0, errNoConstrainedPath},
+		{"s equals t", 2, 2, pathConstraints{}, []int{3}, 0, nil},
+		{"s equals t with a waypoint", 2, 2, pathConstraints{Via: []int{1}}, []int{3, 2, 3}, 11, nil},
+		{"waypoint is the source", 0, 4, pathConstraints{Via: []int{0}}, []int{1, 2, 5}, 2, nil},
+		{"waypoint is the target", 0, 4, pathConstraints{Via: []int{4, 2}}, []int{1, 3, 2, 5}, 3, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path, length, err := constrainedPath(g, c.s, c.t, c.c)
+			if err != c.err {
+				t.Fatalf("error %v, want %v", err, c.err)
+			}
+			if !slices.Equal(path, c.path) || length != c.length {
+				t.Fatalf("path %v of length %v, want %v of length %v", path, length, c.path, c.length)
+			}
+		})
+	}
+}
+
+func TestConstrainedPathBlockedEnd(t *testing.T) {
+	g := matrixGraph(t, "0;1", ";0")
+	for _, c := range []pathConstraints{{BlockedVerts: []int{0}}, {BlockedVerts: []int{1}}} {
+		if _, _, err := constrainedPath(g, 0, 1, c); err == nil {
+			t.Errorf("%v: no error for a blocked end of the path", c)
+		}
+	}
+}
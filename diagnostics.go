@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Graph diagnostics ----------------
+
+type diagLevel int
+
+const (
+	diagInfo diagLevel = iota
+	diagWarning
+	diagError
+)
+
+type diagnostic struct {
+	Level diagLevel
+	Text  string
+}
+
+// diagnoseGraph checks g and lists problems, errors first. cells holds the
+// input errors of matrix cells that could not be applied to g; source is the
+// vertex whose reachability is checked, -1 to skip that check.
+func diagnoseGraph(g *Graph, source int, cells map[[2]int]string) []diagnostic {
+	var out []diagnostic
+	keys := make([][2]int, 0, len(cells))
+	for k := range cells {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a][0] != keys[b][0] {
+			return keys[a][0] < keys[b][0]
+		}
+		return keys[a][1] < keys[b][1]
+	})
+	for _, k := range keys {
+		out = append(out, diagnostic{diagError, fmt.Sprintf("Ячейка (%d, %d): %s", k[0]+1, k[1]+1, cells[k])})
+	}
+
+	for _, cyc := range negativeCycles(g) {
+		out = append(out, diagnostic{diagError, fmt.Sprintf("Отрицательный цикл %s, вес %g", joinPathInts(cyc.path), cyc.weight)})
+	}
+
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if w, ok := g.Edge(i, j); ok && w < 0 {
+				out = append(out, diagnostic{diagWarning, fmt.Sprintf("Отрицательная дуга %s → %s: %g (Дейкстра недоступна)", vertexName(i), vertexName(j), w)})
+			}
+		}
+	}
+
+	for v := 0; v < g.n; v++ {
+		isolated := true
+		for u := 0; u < g.n && isolated; u++ {
+			isolated = !g.HasEdge(v, u) && !g.HasEdge(u, v)
+		}
+		if isolated && g.n > 1 {
+			out = append(out, diagnostic{diagWarning, fmt.Sprintf("Изолированная вершина %s", vertexName(v))})
+		}
+	}
+
+	if source >= 0 && source < g.n {
+		seen := reachableFrom(g, source)
+		var lost []string
+		for v, ok := range seen {
+			if !ok {
+				lost = append(lost, vertexName(v))
+			}
+		}
+		if len(lost) > 0 {
+			out = append(out, diagnostic{diagInfo, fmt.Sprintf("Из вершины %s недостижимы: %s", vertexName(source), strings.Join(lost, ", "))})
+		} else {
+			out = append(out, diagnostic{diagInfo, fmt.Sprintf("Из вершины %s достижимы все вершины", vertexName(source))})
+		}
+	}
+	return out
+}
+
+// reachableFrom marks the vertices reachable from s by a breadth-first search.
+func reachableFrom(g *Graph, s int) []bool {
+	seen := make([]bool, g.n)
+	seen[s] = true
+	queue := []int{s}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for u := 0; u < g.n; u++ {
+			if !seen[u] && g.HasEdge(v, u) {
+				seen[u] = true
+				queue = append(queue, u)
+			}
+		}
+	}
+	return seen
+}
+
+type negCycle struct {
+	path   []int // 1-based, the first vertex repeated at the end
+	weight float64
+}
+
+// negativeCycles finds distinct negative cycles by following the Floyd–
+// Warshall predecessors of every vertex i with d[i][i] < 0 until they repeat.
+// The walk stays on real edges, and each cycle is checked before it is
+// reported.
+func negativeCycles(g *Graph) []negCycle {
+	res, _ := solveFloyd(g, nil)
+	if !res.NegCycle {
+		return nil
+	}
+	var out []negCycle
+	found := make(map[string]bool)
+	for _, i := range res.NegCycleVertices() {
+		at := make(map[int]int)
+		var walk []int
+		for v := i; v >= 0; v = res.pred.At(i, v) {
+			if k, ok := at[v]; ok {
+				// walk[k:] are the cycle vertices in reverse edge order
+				if c, ok := cycleFromWalk(g, walk[k:]); ok && !found[c.key()] {
+					found[c.key()] = true
+					out = append(out, c)
+				}
+				break
+			}
+			at[v] = len(walk)
+			walk = append(walk, v)
+		}
+	}
+	return out
+}
+
+// cycleFromWalk turns a predecessor loop (each vertex preceded by the next)
+// into a forward cycle starting at its smallest vertex.
+func cycleFromWalk(g *Graph, rev []int) (negCycle, bool) {
+	n := len(rev)
+	start := 0
+	for k := range rev {
+		if rev[k] < rev[start] {
+			start = k
+		}
+	}
+	var c negCycle
+	for k := 0; k <= n; k++ {
+		v := rev[(start-k%n+n)%n]
+		c.path = append(c.path, v+1)
+	}
+	for k := 0; k+1 < len(c.path); k++ {
+		w, ok := g.Edge(c.path[k]-1, c.path[k+1]-1)
+		if !ok {
+			return negCycle{}, false
+		}
+		c.weight += w
+	}
+	return c, c.weight < 0
+}
+
+func (c negCycle) key() string { return fmt.Sprint(c.path) }
+
+// diagnosticsPanel shows diagnoseGraph results with a source selector.
+type diagnosticsPanel struct {
+	box    fyne.CanvasObject
+	list   *widget.List
+	source *widget.Select
+	items  []diagnostic
+	n      int
+
+	onSource func()
+}
+
+func newDiagnosticsPanel() *diagnosticsPanel {
+	p := &diagnosticsPanel{}
+	p.list = widget.NewList(
+		func() int { return len(p.items) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			c := co.(*fyne.Container)
+			lbl, icon := c.Objects[0].(*widget.Label), c.Objects[1].(*widget.Icon)
+			it := p.items[id]
+			lbl.SetText(it.Text)
+			switch it.Level {
+			case diagError:
+				lbl.Importance = widget.DangerImportance
+				icon.SetResource(theme.NewErrorThemedResource(theme.ErrorIcon()))
+			case diagWarning:
+				lbl.Importance = widget.WarningImportance
+				icon.SetResource(theme.NewWarningThemedResource(theme.WarningIcon()))
+			default:
+				lbl.Importance = widget.MediumImportance
+				icon.SetResource(theme.InfoIcon())
+			}
+			lbl.Refresh()
+		},
+	)
+	p.list.OnSelected = func(id widget.ListItemID) { p.list.Unselect(id) }
+	p.source = widget.NewSelect(nil, func(string) {
+		if p.onSource != nil {
+			p.onSource()
+		}
+	})
+	p.box = container.NewBorder(container.NewHBox(widget.NewLabel("Источник:"), p.source), nil, nil, nil, p.list)
+	return p
+}
+
+// Source returns the selected source vertex, -1 when there is none.
+func (p *diagnosticsPanel) Source() int { return vertexIndex(p.source.Selected, p.n) }
+
+// SetSource selects vertex v as the source without re-running the checks.
+func (p *diagnosticsPanel) SetSource(v int) {
+	if v >= 0 && v < p.n {
+		p.source.Selected = vertexName(v)
+		p.source.Refresh()
+	}
+}
+
+// Update re-runs the checks for g and returns the number of errors and
+// warnings found.
+func (p *diagnosticsPanel) Update(g *Graph, cells map[[2]int]string) (errors, warnings int) {
+	if g.n != p.n {
+		p.n = g.n
+		opts := make([]string, g.n)
+		for i := range opts {
+			opts[i] = vertexName(i)
+		}
+		p.source.Options = opts
+		if p.Source() < 0 {
+			p.source.Selected = ""
+			if g.n > 0 {
+				p.source.Selected = vertexName(0)
+			}
+		}
+		p.source.Refresh()
+	}
+	p.items = diagnoseGraph(g, p.Source(), cells)
+	for _, it := range p.items {
+		switch it.Level {
+		case diagError:
+			errors++
+		case diagWarning:
+			warnings++
+		}
+	}
+	if errors+warnings == 0 {
+		p.items = append([]diagnostic{{diagInfo, "Проблем не найдено"}}, p.items...)
+	}
+	p.list.Refresh()
+	return errors, warnings
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNegativeCycles(t *testing.T) {
+	cases := []struct {
+		name string
+		g    *Graph
+		want []string // paths with their weights
+	}{
+		{"no cycle", edgesGraph(3, []testEdge{{0, 1, -1}, {1, 2, -1}}), nil},
+		{"non-negative cycle", edgesGraph(2, []testEdge{{0, 1, 2}, {1, 0, -2}}), nil},
+		// 3 is reachable from the cycle and 4 reaches it; neither lies on it
+		{"cycle with tails", edgesGraph(4, []testEdge{{0, 1, 1}, {1, 0, -3}, {1, 2, 1}, {3, 0, 1}}), []string{"[1 2 1] -2"}},
+		{"two cycles", edgesGraph(5, []testEdge{{0, 1, -1}, {1, 0, -1}, {2, 3, 1}, {3, 4, 1}, {4, 2, -3}, {1, 2, 5}}),
+			[]string{"[1 2 1] -2", "[3 4 5 3] -1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got []string
+			for _, cyc := range negativeCycles(c.g) {
+				got = append(got, fmt.Sprint(cyc.path, cyc.weight))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(c.want) {
+				t.Fatalf("cycles %q, want %q", got, c.want)
+			}
+		})
+	}
+}
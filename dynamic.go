@@ -0,0 +1,124 @@
+package main
+
+// ---------------- Dynamic all-pairs updates ----------------
+
+// SetEdge changes edge u→v of the cached graph and repairs the cached results
+// in place instead of dropping them: a cheaper edge is folded in with one
+// O(n²) pass, a dearer or removed one re-solves only the rows whose
+// shortest-path tree used it. Results that cannot be repaired, and exact
+// weight results, are dropped.
+func (c *apspCache) SetEdge(u, v int, val float64, absent bool) {
+	g := c.g
+	if u < 0 || v < 0 || u >= g.n || v >= g.n || u == v {
+		g.SetEdge(u, v, val, absent)
+		return
+	}
+	before := g.Version()
+	oldW, hadOld := g.Edge(u, v)
+	g.SetEdge(u, v, val, absent)
+	newW, hasNew := g.Edge(u, v)
+	for key, e := range c.entries {
+		algo, weights, paths := splitSolverKey(key)
+		sv, _ := solverByKey(algo)
+		if weights != "" || paths != "" || e.version != before || e.err != nil || e.res == nil || (sv.NonNegative && g.HasNegativeEdge()) {
+			delete(c.entries, key)
+			continue
+		}
+		var st SolverStats
+		if !e.res.updateEdge(g, u, v, edgeWeight{oldW, hadOld}, edgeWeight{newW, hasNew}, &st) {
+			delete(c.entries, key)
+			continue
+		}
+		e.version = g.Version()
+		e.res.Version = e.version
+		e.res.Update = st
+		e.res.Updates++
+		c.entries[key] = e
+	}
+}
+
+// edgeWeight is an optional edge weight; ok is false for a missing edge.
+type edgeWeight struct {
+	w  float64
+	ok bool
+}
+
+// less orders weights with a missing edge above every present one.
+func (a edgeWeight) less(b edgeWeight) bool {
+	return a.ok && (!b.ok || a.w < b.w)
+}
+
+// updateEdge repairs r after edge u→v changed from oldW to newW.
+// It returns false when the result has to be recomputed from scratch.
+func (r *APSPResult) updateEdge(g *Graph, u, v int, oldW, newW edgeWeight, st *SolverStats) bool {
+	if r.NegCycle || r.N() != g.n {
+		return false
+	}
+	switch {
+	case newW == oldW:
+		return true
+	case newW.less(oldW):
+		return r.decreaseEdge(u, v, newW.w, st)
+	}
+	r.increaseEdge(g, u, v, st)
+	return true
+}
+
+// decreaseEdge relaxes every pair through the cheaper edge u→v in O(n²).
+func (r *APSPResult) decreaseEdge(u, v int, w float64, st *SolverStats) bool {
+	if dvu, ok := r.Dist(v, u); ok && dvu+w < 0 {
+		return false // the edge closes a negative cycle
+	}
+	n := r.N()
+	for i := 0; i < n; i++ {
+		diu, ok := r.Dist(i, u)
+		if !ok {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			st.iterate()
+			dvj, ok := r.Dist(v, j)
+			if !ok {
+				continue
+			}
+			st.relax()
+			if cand := diu + w + dvj; !r.Reachable(i, j) || cand < r.dist.At(i, j) {
+				st.improve()
+				r.dist.Set(i, j, cand)
+				// row v cannot change here (that would need a negative cycle),
+				// so its predecessors are still the ones of the new path
+				if j == v {
+					r.pred.Set(i, j, u)
+				} else {
+					r.pred.Set(i, j, r.pred.At(v, j))
+				}
+			}
+		}
+	}
+	return true
+}
+
+// increaseEdge re-solves the rows whose shortest-path tree contains u→v.
+// Other rows stay optimal: their tree paths kept their lengths and no path
+// got shorter.
+func (r *APSPResult) increaseEdge(g *Graph, u, v int, st *SolverStats) {
+	var out [][]arc
+	for i := 0; i < r.N(); i++ {
+		st.compare(1)
+		if i == v || r.pred.At(i, v) != u {
+			continue
+		}
+		if g.HasNegativeEdge() {
+			d, p, _ := g.bellmanFordFrom(i, st)
+			copy(r.dist.Row(i), d)
+			copy(r.pred.Row(i), p)
+			continue
+		}
+		if out == nil {
+			out = g.arcs()
+		}
+		d, p := dijkstraArcs(out, i, st)
+		copy(r.dist.Row(i), d)
+		copy(r.pred.Row(i), p)
+	}
+}
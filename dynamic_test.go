@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkAgainstFloyd compares a cached result with a fresh Floyd–Warshall run.
+// Distances must match exactly (weights are small integers). Predecessors may
+// differ between equally short paths, so each cached path is checked to be a
+// real path of the reported length instead.
+func checkAgainstFloyd(t *testing.T, g *Graph, res *APSPResult, err error) {
+	t.Helper()
+	want, wantErr := solveFloyd(g, nil)
+	if err != wantErr {
+		t.Fatalf("error %v, fresh Floyd gives %v", err, wantErr)
+	}
+	if wantErr != nil {
+		return
+	}
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			d, ok := res.Dist(i, j)
+			wd, wok := want.Dist(i, j)
+			if ok != wok || (ok && d != wd) {
+				t.Fatalf("dist %d→%d = %v (%v), fresh Floyd %v (%v)", i+1, j+1, d, ok, wd, wok)
+			}
+			if !ok {
+				continue
+			}
+			path := res.Path(i, j)
+			if len(path) == 0 || path[0] != i+1 || path[len(path)-1] != j+1 {
+				t.Fatalf("path %d→%d = %v", i+1, j+1, path)
+			}
+			var sum float64
+			for k := 0; k+1 < len(path); k++ {
+				w, ok := g.Edge(path[k]-1, path[k+1]-1)
+				if !ok {
+					t.Fatalf("path %d→%d = %v uses a missing edge", i+1, j+1, path)
+				}
+				sum += w
+			}
+			if sum != d {
+				t.Fatalf("path %d→%d = %v has length %v, dist %v", i+1, j+1, path, sum, d)
+			}
+		}
+	}
+}
+
+func TestIncrementalUpdatesMatchFloyd(t *testing.T) {
+	rng := rand.New(rand.NewSource(34))
+	incremental, negCycles := 0, 0
+	for round := 0; round < 200; round++ {
+		n := 2 + rng.Intn(7)
+		g := randomGraph(rng, n, 0.4, func() float64 { return float64(1 + rng.Intn(9)) })
+		cache := newAPSPCache(g)
+		for step := 0; step < 40; step++ {
+			cache.Get("floyd")
+			cache.Get("dijkstra")
+			u, v := rng.Intn(n), rng.Intn(n)
+			if u == v {
+				continue
+			}
+			w, has := g.Edge(u, v)
+			switch op := rng.Intn(4); {
+			case op == 0 && has: // decrease, sometimes below zero
+				cache.SetEdge(u, v, w-float64(1+rng.Intn(6)), false)
+			case op == 1 && has: // increase
+				cache.SetEdge(u, v, w+float64(1+rng.Intn(6)), false)
+			case op == 2 && has: // delete
+				cache.SetEdge(u, v, 0, true)
+			default: // add or overwrite
+				cache.SetEdge(u, v, float64(rng.Intn(12)-2), false)
+			}
+			res, cached, err := cache.Get("floyd")
+			if cached && res.Updates > 0 {
+				incremental++
+			}
+			if err == errNegativeCycle {
+				negCycles++
+			}
+			checkAgainstFloyd(t, g, res, err)
+			if res, _, err := cache.Get("dijkstra"); err == nil {
+				checkAgainstFloyd(t, g, res, err)
+			} else if !g.HasNegativeEdge() {
+				t.Fatalf("dijkstra failed without negative edges: %v", err)
+			}
+		}
+	}
+	if incremental == 0 || negCycles == 0 {
+		t.Fatalf("test did not exercise both paths: %d incremental updates, %d negative cycles", incremental, negCycles)
+	}
+}
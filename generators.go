@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Random graph generators ----------------
+
+// genParams are shared by all generators. P is the edge probability for
+// gnp/dag/negcycle and the connection radius (in unit-square coordinates)
+// for geometric graphs.
+type genParams struct {
+	N          int
+	P          float64
+	Seed       uint64
+	MinW, MaxW float64
+	NegProb    float64 // probability that a weight is negated
+	Integer    bool    // round weights to integers
+}
+
+type graphGenerator struct {
+	Key   string
+	Title string
+	Gen   func(p genParams, rng *rand.Rand) (*Graph, error)
+}
+
+var graphGenerators = []graphGenerator{
+	{Key: "gnp", Title: "Эрдёш–Реньи G(n, p)", Gen: genGNP},
+	{Key: "dag", Title: "Случайный DAG", Gen: genDAG},
+	{Key: "grid", Title: "Решётка", Gen: genGrid},
+	{Key: "complete", Title: "Полный граф", Gen: genComplete},
+	{Key: "geometric", Title: "Геометрический (евклидовы веса)", Gen: genGeometric},
+	{Key: "negcycle", Title: "С отрицательным циклом", Gen: genNegCycle},
+}
+
+func generatorByKey(key string) (graphGenerator, bool) {
+	for _, gen := range graphGenerators {
+		if gen.Key == key {
+			return gen, true
+		}
+	}
+	return graphGenerator{}, false
+}
+
+// generateGraph validates p and runs the generator with a seeded PRNG, so the
+// same parameters always give the same graph.
+func generateGraph(key string, p genParams) (*Graph, error) {
+	gen, ok := generatorByKey(key)
+	if !ok {
+		return nil, fmt.Errorf("неизвестный генератор %q", key)
+	}
+	switch {
+	case p.N < 0:
+		return nil, fmt.Errorf("число вершин должно быть ≥ 0")
+	case p.P < 0 || (p.P > 1 && key != "geometric"):
+		return nil, fmt.Errorf("вероятность дуги должна быть от 0 до 1")
+	case p.MinW > p.MaxW:
+		return nil, fmt.Errorf("минимальный вес больше максимального")
+	case p.NegProb < 0 || p.NegProb > 1:
+		return nil, fmt.Errorf("вероятность отрицательного веса должна быть от 0 до 1")
+	}
+	for _, w := range []float64{p.MinW, p.MaxW} {
+		if err := checkWeight(w); err != nil {
+			return nil, err
+		}
+	}
+	return gen.Gen(p, rand.New(rand.NewPCG(p.Seed, p.Seed^0x9e3779b97f4a7c15)))
+}
+
+func (p genParams) weight(rng *rand.Rand) float64 {
+	w := p.MinW + rng.Float64()*(p.MaxW-p.MinW)
+	if p.Integer {
+		w = math.Round(w)
+	}
+	if rng.Float64() < p.NegProb {
+		w = -w
+	}
+	return w
+}
+
+func newSizedGraph(n int) *Graph {
+	g := NewGraph()
+	g.Resize(n)
+	return g
+}
+
+func genGNP(p genParams, rng *rand.Rand) (*Graph, error) {
+	g := newSizedGraph(p.N)
+	for i := 0; i < p.N; i++ {
+		for j := 0; j < p.N; j++ {
+			if i != j && rng.Float64() < p.P {
+				g.SetEdge(i, j, p.weight(rng), false)
+			}
+		}
+	}
+	return g, nil
+}
+
+// genDAG orients edges along a random vertex order, so there are no cycles
+// and negative weights are always safe.
+func genDAG(p genParams, rng *rand.Rand) (*Graph, error) {
+	g := newSizedGraph(p.N)
+	order := rng.Perm(p.N)
+	for a := 0; a < p.N; a++ {
+		for b := a + 1; b < p.N; b++ {
+			if rng.Float64() < p.P {
+				g.SetEdge(order[a], order[b], p.weight(rng), false)
+			}
+		}
+	}
+	return g, nil
+}
+
+// genGrid places the vertices row by row on a ⌈√n⌉-wide lattice and links
+// horizontal and vertical neighbours in both directions.
+func genGrid(p genParams, rng *rand.Rand) (*Graph, error) {
+	g := newSizedGraph(p.N)
+	cols := int(math.Ceil(math.Sqrt(float64(p.N))))
+	for v := 0; v < p.N; v++ {
+		r, c := v/max(cols, 1), v%max(cols, 1)
+		g.SetPos(v, Point{X: 60 + float64(c)*80, Y: 50 + float64(r)*80})
+		for _, u := range []int{v + 1, v + cols} {
+			if (u == v+1 && c+1 == cols) || u >= p.N {
+				continue
+			}
+			g.SetEdge(v, u, p.weight(rng), false)
+			g.SetEdge(u, v, p.weight(rng), false)
+		}
+	}
+	return g, nil
+}
+
+func genComplete(p genParams, rng *rand.Rand) (*Graph, error) {
+	g := newSizedGraph(p.N)
+	for i := 0; i < p.N; i++ {
+		for j := 0; j < p.N; j++ {
+			if i != j {
+				g.SetEdge(i, j, p.weight(rng), false)
+			}
+		}
+	}
+	return g, nil
+}
+
+// genGeometric drops points into the unit square and links pairs closer than
+// the radius P in both directions. Weights are the distances scaled by MaxW,
+// so the negative-weight probability and MinW do not apply.
+func genGeometric(p genParams, rng *rand.Rand) (*Graph, error) {
+	g := newSizedGraph(p.N)
+	pts := make([]Point, p.N)
+	for i := range pts {
+		pts[i] = Point{X: rng.Float64(), Y: rng.Float64()}
+		g.SetPos(i, Point{X: 40 + pts[i].X*420, Y: 40 + pts[i].Y*280})
+	}
+	for i := 0; i < p.N; i++ {
+		for j := 0; j < p.N; j++ {
+			d := math.Hypot(pts[i].X-pts[j].X, pts[i].Y-pts[j].Y)
+			if i == j || d > p.P {
+				continue
+			}
+			w := d * p.MaxW
+			if p.Integer {
+				w = math.Max(1, math.Round(w))
+			}
+			g.SetEdge(i, j, w, false)
+		}
+	}
+	return g, nil
+}
+
+// genNegCycle builds a G(n, p) graph and plants a cycle through 2–5 random
+// vertices whose closing edge makes the total weight -1.
+func genNegCycle(p genParams, rng *rand.Rand) (*Graph, error) {
+	if p.N < 2 {
+		return nil, fmt.Errorf("для отрицательного цикла нужно хотя бы 2 вершины")
+	}
+	g, _ := genGNP(p, rng)
+	k := min(p.N, 2+rng.IntN(4))
+	cyc := rng.Perm(p.N)[:k]
+	sum := 0.0
+	for t := 0; t+1 < k; t++ {
+		w := math.Abs(p.weight(rng))
+		g.SetEdge(cyc[t], cyc[t+1], w, false)
+		sum += w
+	}
+	if err := checkWeight(-sum - 1); err != nil {
+		return nil, err
+	}
+	g.SetEdge(cyc[k-1], cyc[0], -sum-1, false)
+	return g, nil
+}
+
+// showGeneratorDialog asks for generator parameters and hands the new graph
+// to onDone together with a status line that includes the seed.
+func showGeneratorDialog(w fyne.Window, onDone func(g *Graph, desc string)) {
+	var titles []string
+	for _, gen := range graphGenerators {
+		titles = append(titles, gen.Title)
+	}
+	kindSel := widget.NewSelect(titles, nil)
+	kindSel.SetSelectedIndex(0)
+	nEntry := widget.NewEntry()
+	nEntry.SetText(strconv.Itoa(min(6, MaxVertices)))
+	pEntry := widget.NewEntry()
+	pEntry.SetText("0.4")
+	seedEntry := widget.NewEntry()
+	seedEntry.SetPlaceHolder("пусто — случайное")
+	minEntry := widget.NewEntry()
+	minEntry.SetText("1")
+	maxEntry := widget.NewEntry()
+	maxEntry.SetText("10")
+	negEntry := widget.NewEntry()
+	negEntry.SetText("0")
+	intCheck := widget.NewCheck("целые веса", nil)
+	intCheck.SetChecked(true)
+
+	dialog.ShowForm("Случайный граф", "Создать", "Отмена", []*widget.FormItem{
+		widget.NewFormItem("Тип", kindSel),
+		widget.NewFormItem(fmt.Sprintf("Вершин (≤%d)", MaxVertices), nEntry),
+		widget.NewFormItem("p / радиус", pEntry),
+		widget.NewFormItem("Зерно", seedEntry),
+		widget.NewFormItem("Мин. вес", minEntry),
+		widget.NewFormItem("Макс. вес", maxEntry),
+		widget.NewFormItem("P(вес < 0)", negEntry),
+		widget.NewFormItem("", intCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		p := genParams{Integer: intCheck.Checked}
+		var err error
+		p.N, err = strconv.Atoi(strings.TrimSpace(nEntry.Text))
+		if err != nil || p.N < 0 || p.N > MaxVertices {
+			dialog.ShowError(fmt.Errorf("Число вершин должно быть от 0 до %d", MaxVertices), w)
+			return
+		}
+		if s := strings.TrimSpace(seedEntry.Text); s == "" {
+			p.Seed = rand.Uint64() % 1000000
+		} else if p.Seed, err = strconv.ParseUint(s, 10, 64); err != nil {
+			dialog.ShowError(fmt.Errorf("Зерно должно быть неотрицательным целым"), w)
+			return
+		}
+		for _, f := range []struct {
+			e   *widget.Entry
+			dst *float64
+		}{{pEntry, &p.P}, {minEntry, &p.MinW}, {maxEntry, &p.MaxW}, {negEntry, &p.NegProb}} {
+			v, absent, err := infOrFloat(f.e.Text)
+			if err != nil || absent {
+				dialog.ShowError(fmt.Errorf("Некорректное число: %q", f.e.Text), w)
+				return
+			}
+			*f.dst = v
+		}
+		gen := graphGenerators[kindSel.SelectedIndex()]
+		g, err := generateGraph(gen.Key, p)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		onDone(g, fmt.Sprintf("%s: %d вершин, зерно %d", gen.Title, g.n, p.Seed))
+	}, w)
+}
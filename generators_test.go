@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func arcCount(g *Graph) int {
+	m := 0
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if g.HasEdge(i, j) {
+				m++
+			}
+		}
+	}
+	return m
+}
+
+func TestGeneratorEdgeCounts(t *testing.T) {
+	base := genParams{Seed: 7, MinW: 1, MaxW: 10, Integer: true}
+	with := func(n int, p float64) genParams { q := base; q.N, q.P = n, p; return q }
+	cases := []struct {
+		key  string
+		p    genParams
+		want int
+	}{
+		{"gnp", with(12, 0), 0},
+		{"gnp", with(12, 1), 12 * 11},
+		{"dag", with(12, 1), 12 * 11 / 2},
+		{"complete", with(7, 0), 7 * 6},
+		{"complete", with(1, 0), 0},
+		{"grid", with(9, 0), 24}, // 3×3: 12 neighbour pairs, both directions
+		{"grid", with(7, 0), 16}, // rows 3, 3, 1: 4 horizontal and 4 vertical pairs
+		{"geometric", with(10, 2), 10 * 9},
+		{"geometric", with(10, 0), 0},
+	}
+	for _, c := range cases {
+		g, err := generateGraph(c.key, c.p)
+		if err != nil {
+			t.Fatalf("%s n=%d p=%v: %v", c.key, c.p.N, c.p.P, err)
+		}
+		if g.n != c.p.N || arcCount(g) != c.want {
+			t.Errorf("%s n=%d p=%v: %d vertices, %d arcs, want %d arcs", c.key, c.p.N, c.p.P, g.n, arcCount(g), c.want)
+		}
+	}
+}
+
+func TestGeneratorSeeds(t *testing.T) {
+	p := genParams{N: 15, P: 0.5, Seed: 42, MinW: -5, MaxW: 20, NegProb: 0.3}
+	for _, gen := range graphGenerators {
+		a, err := generateGraph(gen.Key, p)
+		if err != nil {
+			t.Fatalf("%s: %v", gen.Key, err)
+		}
+		b, _ := generateGraph(gen.Key, p)
+		q := p
+		q.Seed++
+		c, _ := generateGraph(gen.Key, q)
+		same, differs := true, false
+		for i := 0; i < p.N; i++ {
+			for j := 0; j < p.N; j++ {
+				aw, aok := a.Edge(i, j)
+				bw, bok := b.Edge(i, j)
+				cw, cok := c.Edge(i, j)
+				same = same && aw == bw && aok == bok
+				differs = differs || aw != cw || aok != cok
+			}
+		}
+		if !same {
+			t.Errorf("%s: the same seed gave different graphs", gen.Key)
+		}
+		if !differs {
+			t.Errorf("%s: seeds %d and %d gave the same graph", gen.Key, p.Seed, q.Seed)
+		}
+	}
+}
+
+func TestGeneratorNegCycle(t *testing.T) {
+	for seed := uint64(0); seed < 30; seed++ {
+		for _, n := range []int{2, 3, 10} {
+			g, err := generateGraph("negcycle", genParams{N: n, P: 0.2, Seed: seed, MinW: 1, MaxW: 10, Integer: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := solveFloyd(g, nil); !errors.Is(err, errNegativeCycle) {
+				t.Fatalf("seed %d, n=%d: Floyd gives %v, want a negative cycle", seed, n, err)
+			}
+		}
+	}
+	// a DAG stays acyclic however negative its weights are
+	g, _ := generateGraph("dag", genParams{N: 10, P: 1, Seed: 3, MinW: 1, MaxW: 10, NegProb: 1})
+	if _, err := solveFloyd(g, nil); err != nil {
+		t.Fatalf("negative DAG: %v", err)
+	}
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	ok := genParams{N: 5, P: 0.5, MinW: 1, MaxW: 2}
+	cases := []struct {
+		key  string
+		edit func(p *genParams)
+		want string
+	}{
+		{"astar", func(p *genParams) {}, "неизвестный генератор"},
+		{"gnp", func(p *genParams) { p.N = -1 }, "число вершин"},
+		{"gnp", func(p *genParams) { p.P = 1.5 }, "вероятность дуги"},
+		{"dag", func(p *genParams) { p.P = -0.1 }, "вероятность дуги"},
+		{"gnp", func(p *genParams) { p.MinW = 3 }, "минимальный вес"},
+		{"gnp", func(p *genParams) { p.NegProb = 2 }, "отрицательного веса"},
+		{"negcycle", func(p *genParams) { p.N = 1 }, "хотя бы 2 вершины"},
+	}
+	for _, c := range cases {
+		p := ok
+		c.edit(&p)
+		if _, err := generateGraph(c.key, p); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s %+v: err = %v, want %q", c.key, p, err, c.want)
+		}
+	}
+	// the radius of a geometric graph may exceed 1
+	p := ok
+	p.P = 1.5
+	if _, err := generateGraph("geometric", p); err != nil {
+		t.Errorf("geometric radius 1.5: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ---------------- CSV input/output ----------------
+
+// maxGraphVertices bounds graphs read from files; Floyd needs O(n²) memory
+// per result, so a stray vertex id of 10⁹ must not reach Resize.
+const maxGraphVertices = 2000
+
+// readGraphCSV reads a graph either as an n×n weight matrix (format "matrix",
+// empty/inf/∞ cells mean no edge) or as "u;v;w" lines with 1-based vertices
+// (format "edges"). Lines starting with '#' are ignored.
+func readGraphCSV(in io.Reader, format string, sep rune) (*Graph, error) {
+	rd := csv.NewReader(in)
+	rd.Comma = sep
+	rd.Comment = '#'
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	rows, err := rd.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "matrix":
+		return graphFromMatrixRows(rows)
+	case "edges":
+		return graphFromEdgeRows(rows)
+	}
+	return nil, fmt.Errorf("неизвестный формат %q (ожидается matrix или edges)", format)
+}
+
+func graphFromMatrixRows(rows [][]string) (*Graph, error) {
+	n := len(rows)
+	if n > maxGraphVertices {
+		return nil, fmt.Errorf("%d строк, допускается не больше %d вершин", n, maxGraphVertices)
+	}
+	g := NewGraph()
+	g.Resize(n)
+	for i, row := range rows {
+		if len(row) != n {
+			return nil, fmt.Errorf("строка %d: %d значений, ожидалось %d", i+1, len(row), n)
+		}
+		for j, cell := range row {
+			v, absent, err := infOrFloat(cell)
+			if err != nil {
+				return nil, fmt.Errorf("ячейка (%d, %d): %v", i+1, j+1, err)
+			}
+			if i == j {
+				if !absent && v != 0 {
+					return nil, fmt.Errorf("ячейка (%d, %d): на диагонали допускается только 0", i+1, j+1)
+				}
+				continue
+			}
+			g.SetEdge(i, j, v, absent)
+			g.SetExactText(i, j, cell)
+		}
+	}
+	return g, nil
+}
+
+func graphFromEdgeRows(rows [][]string) (*Graph, error) {
+	type edge struct {
+		u, v int
+		w    float64
+		text string
+	}
+	var edges []edge
+	seen := make(map[[2]int]bool)
+	n := 0
+	for k, row := range rows {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("строка %d: ожидается u;v;w", k+1)
+		}
+		u, errU := strconv.Atoi(strings.TrimSpace(row[0]))
+		v, errV := strconv.Atoi(strings.TrimSpace(row[1]))
+		if k == 0 && (errU != nil || errV != nil) {
+			continue // header
+		}
+		if errU != nil || errV != nil || u < 1 || v < 1 {
+			return nil, fmt.Errorf("строка %d: номера вершин должны быть целыми ≥ 1", k+1)
+		}
+		if u > maxGraphVertices || v > maxGraphVertices {
+			return nil, fmt.Errorf("строка %d: номер вершины больше %d", k+1, maxGraphVertices)
+		}
+		w, absent, err := infOrFloat(row[2])
+		switch {
+		case absent:
+			return nil, fmt.Errorf("строка %d: некорректный вес %q", k+1, row[2])
+		case err != nil:
+			return nil, fmt.Errorf("строка %d: вес %q: %v", k+1, row[2], err)
+		}
+		if u == v {
+			return nil, fmt.Errorf("строка %d: петли не поддерживаются", k+1)
+		}
+		if seen[[2]int{u, v}] {
+			return nil, fmt.Errorf("строка %d: дуга %d → %d повторяется", k+1, u, v)
+		}
+		seen[[2]int{u, v}] = true
+		edges = append(edges, edge{u - 1, v - 1, w, row[2]})
+		n = max(n, u, v)
+	}
+	g := NewGraph()
+	g.Resize(n)
+	for _, e := range edges {
+		g.SetEdge(e.u, e.v, e.w, false)
+		g.SetExactText(e.u, e.v, e.text)
+	}
+	return g, nil
+}
+
+// writeGraphCSV is the inverse of readGraphCSV; absent edges are written as
+// "inf" in the matrix layout.
+func writeGraphCSV(out io.Writer, g *Graph, format string, sep rune) error {
+	wrt := csv.NewWriter(out)
+	wrt.Comma = sep
+	switch format {
+	case "matrix":
+		for i := 0; i < g.n; i++ {
+			row := make([]string, g.n)
+			for j := range row {
+				row[j] = floatToCell(g, i, j)
+				if row[j] == "" {
+					row[j] = "inf"
+				}
+			}
+			wrt.Write(row)
+		}
+	case "edges":
+		wrt.Write([]string{"u", "v", "w"})
+		for i := 0; i < g.n; i++ {
+			for j := 0; j < g.n; j++ {
+				if g.HasEdge(i, j) {
+					wrt.Write([]string{strconv.Itoa(i + 1), strconv.Itoa(j + 1), g.WeightText(i, j)})
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("неизвестный формат %q (ожидается matrix или edges)", format)
+	}
+	wrt.Flush()
+	return wrt.Error()
+}
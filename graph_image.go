@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/theme"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// ---------------- Image export (SVG / PNG) ----------------
+
+// graphScene is what the editor shows, detached from the widget so it can be
+// drawn off-screen. Coordinates are editor pixels.
+type graphScene struct {
+	Verts      []Point
+	Labels     []string
+	Edges      []edgeRec
+	Highlight  map[[2]int]bool
+	Start, End int
+	Comp       []int            // component of every vertex for colouring, nil for none
+	Roles      []vertexRole     // center/periphery outlines, nil for none
+	Scale      *centralityScale // betweenness sizes, nil for the normal look
+	Badges     []string         // text next to every vertex, nil for none
+	Marks      map[int]vertexMark
+	Blocked    map[[2]int]bool
+}
+
+func (gc *GraphCanvas) scene() graphScene {
+	sc := graphScene{Edges: gc.edges, Labels: gc.labels, Highlight: gc.highlightPairs, Start: gc.startIdx, End: gc.endIdx, Comp: gc.comp, Roles: gc.roles, Scale: gc.scale, Badges: gc.badges, Marks: gc.marks, Blocked: gc.blocked}
+	for _, p := range gc.verts {
+		sc.Verts = append(sc.Verts, Point{X: float64(p.X), Y: float64(p.Y)})
+	}
+	return sc
+}
+
+// sceneFromGraph lays g out like the editor would, highlighting path
+// (1-based vertex numbers) when it is not empty.
+func sceneFromGraph(g *Graph, path []int) graphScene {
+	sc := graphScene{Labels: g.labels, Highlight: make(map[[2]int]bool), Start: -1, End: -1}
+	for i := 0; i < g.n; i++ {
+		p, ok := g.Pos(i)
+		if !ok {
+			p = circlePos(i, g.n)
+		}
+		sc.Verts = append(sc.Verts, p)
+		for j := 0; j < g.n; j++ {
+			if w, ok := g.Edge(i, j); ok {
+				sc.Edges = append(sc.Edges, edgeRec{U: i, V: j, W: w})
+			}
+		}
+	}
+	for k := 0; k+1 < len(path); k++ {
+		sc.Highlight[[2]int{path[k] - 1, path[k+1] - 1}] = true
+	}
+	if len(path) > 0 {
+		sc.Start, sc.End = path[0]-1, path[len(path)-1]-1
+	}
+	return sc
+}
+
+// painter is a minimal vector drawing target; text is centred on (x, y).
+type painter interface {
+	line(x1, y1, x2, y2, width float64, c color.NRGBA)
+	polygon(pts []Point, c color.NRGBA)
+	circle(cx, cy, r float64, fill, stroke color.NRGBA, strokeWidth float64)
+	text(x, y float64, s string, size float64, bold bool, c color.NRGBA)
+}
+
+// paint draws the scene scaled uniformly to fit width×height.
+func (sc graphScene) paint(p painter, width, height int) {
+	const margin = 12.0
+	r := float64(vertexR)
+	minX, minY, maxX, maxY := 0.0, 0.0, 1.0, 1.0
+	for i, v := range sc.Verts {
+		rv := r * sc.Scale.vertexK(i)
+		if i == 0 {
+			minX, minY, maxX, maxY = v.X-rv, v.Y-rv, v.X+rv, v.Y+rv
+		}
+		half := rv
+		if i < len(sc.Labels) {
+			// labels are centred under the vertex; ~6.5 px per character at size 11
+			half = math.Max(half, float64(utf8.RuneCountInString(sc.Labels[i]))*3.25)
+		}
+		right, top := half, rv
+		if i < len(sc.Badges) {
+			right = math.Max(right, rv*0.7+badgeWidth(sc.Badges[i]))
+			top = rv + 8
+		}
+		minX, maxX = math.Min(minX, v.X-half), math.Max(maxX, v.X+right)
+		minY, maxY = math.Min(minY, v.Y-top), math.Max(maxY, v.Y+rv)
+	}
+	minX, maxX = minX-margin, maxX+margin
+	minY = minY - margin
+	maxY = maxY + margin + 14 // room for labels
+	k := math.Min(float64(width)/(maxX-minX), float64(height)/(maxY-minY))
+	offX := (float64(width) - (maxX-minX)*k) / 2
+	offY := (float64(height) - (maxY-minY)*k) / 2
+	tr := func(v Point) (float64, float64) { return offX + (v.X-minX)*k, offY + (v.Y-minY)*k }
+
+	for _, e := range sc.Edges {
+		x1, y1 := tr(sc.Verts[e.U])
+		x2, y2 := tr(sc.Verts[e.V])
+		dx, dy := x2-x1, y2-y1
+		l := math.Hypot(dx, dy)
+		r1, r2 := r*k*sc.Scale.vertexK(e.U), r*k*sc.Scale.vertexK(e.V)
+		if l <= r1+r2 {
+			continue
+		}
+		ux, uy := dx/l, dy/l
+		c, w := edgeColor, 2.0
+		if sc.Highlight[[2]int{e.U, e.V}] {
+			c, w = pathColor, 3.0
+		}
+		if sc.Blocked[[2]int{e.U, e.V}] {
+			c = blockedColor
+		}
+		w *= sc.Scale.edgeK(e.U, e.V)
+		// stop at the circles and leave room for the arrowhead
+		ax, ay := x2-ux*r2, y2-uy*r2
+		head, half := 11*k, 5*k
+		p.line(x1+ux*r1, y1+uy*r1, ax-ux*head*0.8, ay-uy*head*0.8, w*k, c)
+		p.polygon([]Point{
+			{X: ax, Y: ay},
+			{X: ax - ux*head - uy*half, Y: ay - uy*head + ux*half},
+			{X: ax - ux*head + uy*half, Y: ay - uy*head - ux*half},
+		}, c)
+		p.text((x1+x2)/2+uy*10*k, (y1+y2)/2-ux*10*k, strconv.FormatFloat(e.W, 'g', -1, 64), 12*k, false, textColor)
+	}
+	for i, v := range sc.Verts {
+		x, y := tr(v)
+		stroke, sw := vertexOutline(i, sc.Roles)
+		fill, stroke, sw := sc.Marks[i].style(vertexFill(i, sc.Start, sc.End, sc.Comp), stroke, sw)
+		p.circle(x, y, r*k*sc.Scale.vertexK(i), fill, stroke, sw*k)
+		p.text(x, y, vertexName(i), 12*k, true, textColor)
+		if i < len(sc.Labels) && sc.Labels[i] != "" {
+			p.text(x, y+(r*sc.Scale.vertexK(i)+9)*k, sc.Labels[i], 11*k, false, labelColor)
+		}
+		if i < len(sc.Badges) {
+			rv := r * sc.Scale.vertexK(i)
+			bw, bh := badgeWidth(sc.Badges[i]), 15.0
+			x0, y0 := x+rv*0.7*k, y-(rv+bh*0.6)*k
+			x1, y1 := x0+bw*k, y0+bh*k
+			p.polygon([]Point{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}, badgeFill)
+			p.text((x0+x1)/2, (y0+y1)/2, sc.Badges[i], 11*k, false, badgeText)
+		}
+	}
+}
+
+// badgeWidth estimates the width of a distance badge in editor pixels.
+func badgeWidth(s string) float64 { return float64(utf8.RuneCountInString(s))*6.5 + 8 }
+
+// imageFormatFor picks svg or png from a key or a file extension.
+func imageFormatFor(name string) (string, bool) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	for _, f := range []string{strings.ToLower(name), ext} {
+		if f == "svg" || f == "png" {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// writeSceneImage renders sc as "svg" or "png" at width×height pixels.
+func writeSceneImage(w io.Writer, sc graphScene, format string, width, height int) error {
+	if width < 16 || height < 16 || width > 16384 || height > 16384 {
+		return fmt.Errorf("размер изображения должен быть от 16 до 16384 пикселей")
+	}
+	switch format {
+	case "svg":
+		bw := bufio.NewWriter(w)
+		sp := &svgPainter{w: bw}
+		fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\">\n", width, height, width, height)
+		bw.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"#ffffff\"/>\n")
+		sc.paint(sp, width, height)
+		bw.WriteString("</svg>\n")
+		return bw.Flush()
+	case "png":
+		img, err := renderScene(sc, width, height)
+		if err != nil {
+			return err
+		}
+		return png.Encode(w, img)
+	}
+	return fmt.Errorf("неизвестный формат изображения %q (ожидается svg или png)", format)
+}
+
+// renderScene rasterizes sc onto a white width×height image.
+func renderScene(sc graphScene, width, height int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	pp, err := newPNGPainter(img)
+	if err != nil {
+		return nil, err
+	}
+	sc.paint(pp, width, height)
+	return img, nil
+}
+
+// ---- SVG ----
+
+type svgPainter struct{ w *bufio.Writer }
+
+func svgColor(c color.NRGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+
+func svgNum(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+func (p *svgPainter) line(x1, y1, x2, y2, width float64, c color.NRGBA) {
+	fmt.Fprintf(p.w, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+		svgNum(x1), svgNum(y1), svgNum(x2), svgNum(y2), svgColor(c), svgNum(width))
+}
+
+func (p *svgPainter) polygon(pts []Point, c color.NRGBA) {
+	parts := make([]string, len(pts))
+	for i, q := range pts {
+		parts[i] = svgNum(q.X) + "," + svgNum(q.Y)
+	}
+	fmt.Fprintf(p.w, "<polygon points=\"%s\" fill=\"%s\"/>\n", strings.Join(parts, " "), svgColor(c))
+}
+
+func (p *svgPainter) circle(cx, cy, r float64, fill, stroke color.NRGBA, sw float64) {
+	fmt.Fprintf(p.w, "<circle cx=\"%s\" cy=\"%s\" r=\"%s\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+		svgNum(cx), svgNum(cy), svgNum(r), svgColor(fill), svgColor(stroke), svgNum(sw))
+}
+
+func (p *svgPainter) text(x, y float64, s string, size float64, bold bool, c color.NRGBA) {
+	weight := ""
+	if bold {
+		weight = ` font-weight="bold"`
+	}
+	fmt.Fprintf(p.w, "<text x=\"%s\" y=\"%s\" font-size=\"%s\"%s fill=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\">%s</text>\n",
+		svgNum(x), svgNum(y), svgNum(size), weight, svgColor(c), html.EscapeString(s))
+}
+
+// ---- PNG ----
+
+// The raster text uses the fonts bundled with the Fyne theme.
+var themeFonts = sync.OnceValues(func() ([2]*opentype.Font, error) {
+	var out [2]*opentype.Font
+	for i, res := range []interface{ Content() []byte }{theme.DefaultTextFont(), theme.DefaultTextBoldFont()} {
+		f, err := opentype.Parse(res.Content())
+		if err != nil {
+			return out, err
+		}
+		out[i] = f
+	}
+	return out, nil
+})
+
+type pngPainter struct {
+	img   *image.RGBA
+	ras   *vector.Rasterizer
+	fonts [2]*opentype.Font
+	faces map[[2]float64]font.Face
+}
+
+func newPNGPainter(img *image.RGBA) (*pngPainter, error) {
+	fonts, err := themeFonts()
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	return &pngPainter{img: img, ras: vector.NewRasterizer(b.Dx(), b.Dy()), fonts: fonts, faces: make(map[[2]float64]font.Face)}, nil
+}
+
+func (p *pngPainter) fill(pts []Point, c color.NRGBA) {
+	b := p.img.Bounds()
+	p.ras.Reset(b.Dx(), b.Dy())
+	p.ras.DrawOp = draw.Over
+	for i, q := range pts {
+		if i == 0 {
+			p.ras.MoveTo(float32(q.X), float32(q.Y))
+		} else {
+			p.ras.LineTo(float32(q.X), float32(q.Y))
+		}
+	}
+	p.ras.ClosePath()
+	p.ras.Draw(p.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (p *pngPainter) line(x1, y1, x2, y2, width float64, c color.NRGBA) {
+	l := math.Hypot(x2-x1, y2-y1)
+	if l == 0 {
+		return
+	}
+	nx, ny := -(y2-y1)/l*width/2, (x2-x1)/l*width/2
+	p.fill([]Point{{X: x1 + nx, Y: y1 + ny}, {X: x2 + nx, Y: y2 + ny}, {X: x2 - nx, Y: y2 - ny}, {X: x1 - nx, Y: y1 - ny}}, c)
+}
+
+func (p *pngPainter) polygon(pts []Point, c color.NRGBA) { p.fill(pts, c) }
+
+func circlePoints(cx, cy, r float64) []Point {
+	const steps = 72
+	pts := make([]Point, steps)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / steps
+		pts[i] = Point{X: cx + r*math.Cos(a), Y: cy + r*math.Sin(a)}
+	}
+	return pts
+}
+
+func (p *pngPainter) circle(cx, cy, r float64, fill, stroke color.NRGBA, sw float64) {
+	p.fill(circlePoints(cx, cy, r+sw/2), stroke)
+	p.fill(circlePoints(cx, cy, r-sw/2), fill)
+}
+
+func (p *pngPainter) text(x, y float64, s string, size float64, bold bool, c color.NRGBA) {
+	style := 0.0
+	if bold {
+		style = 1
+	}
+	key := [2]float64{size, style}
+	face, ok := p.faces[key]
+	if !ok {
+		var err error
+		face, err = opentype.NewFace(p.fonts[int(style)], &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return
+		}
+		p.faces[key] = face
+	}
+	d := &font.Drawer{Dst: p.img, Src: image.NewUniform(c), Face: face}
+	m := face.Metrics()
+	adv := d.MeasureString(s)
+	d.Dot = fixed.Point26_6{
+		X: fixed.Int26_6(x*64) - adv/2,
+		Y: fixed.Int26_6(y*64) + (m.Ascent-m.Descent)/2,
+	}
+	d.DrawString(s)
+}
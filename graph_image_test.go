@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestWriteSceneImageSize(t *testing.T) {
+	sc := sceneFromGraph(edgesGraph(3, []testEdge{{0, 1, 1}, {1, 2, -2}}), []int{1, 2, 3})
+	cases := []struct {
+		width, height int
+		ok            bool
+	}{
+		{16, 16, true},
+		{1000, 750, true},
+		{16384, 16, true},
+		{15, 100, false},
+		{100, 0, false},
+		{16385, 100, false},
+		{100, -5, false},
+	}
+	for _, format := range []string{"svg", "png"} {
+		for _, c := range cases {
+			if format == "png" && c.width*c.height > 1<<20 {
+				continue // valid but slow to rasterise
+			}
+			t.Run(fmt.Sprintf("%s/%dx%d", format, c.width, c.height), func(t *testing.T) {
+				var buf bytes.Buffer
+				err := writeSceneImage(&buf, sc, format, c.width, c.height)
+				if !c.ok {
+					if err == nil || !strings.Contains(err.Error(), "от 16 до 16384") {
+						t.Fatalf("err = %v, want a size error", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if format == "svg" {
+					if head := fmt.Sprintf(`width="%d" height="%d"`, c.width, c.height); !strings.Contains(buf.String(), head) {
+						t.Errorf("svg header lacks %s", head)
+					}
+					return
+				}
+				img, err := png.Decode(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b := img.Bounds(); b.Dx() != c.width || b.Dy() != c.height {
+					t.Errorf("png is %dx%d", b.Dx(), b.Dy())
+				}
+			})
+		}
+	}
+}
+
+// boundsPainter records whether every vertex circle fits the image.
+type boundsPainter struct {
+	width, height float64
+	outside       []string
+}
+
+func (p *boundsPainter) line(x1, y1, x2, y2, w float64, c color.NRGBA)                  {}
+func (p *boundsPainter) polygon(pts []Point, c color.NRGBA)                             {}
+func (p *boundsPainter) text(x, y float64, s string, sz float64, b bool, c color.NRGBA) {}
+
+func (p *boundsPainter) circle(cx, cy, r float64, fill, stroke color.NRGBA, sw float64) {
+	if cx-r < 0 || cy-r < 0 || cx+r > p.width || cy+r > p.height {
+		p.outside = append(p.outside, fmt.Sprintf("(%.1f, %.1f) r=%.1f", cx, cy, r))
+	}
+}
+
+// The scene is scaled to the requested size whatever the editor coordinates.
+func TestScenePaintFits(t *testing.T) {
+	g := edgesGraph(3, []testEdge{{0, 1, 1}, {1, 2, 1}})
+	g.SetPos(0, Point{X: -500, Y: 20})
+	g.SetPos(1, Point{X: 4000, Y: 3000})
+	g.SetPos(2, Point{X: 10, Y: -10})
+	for _, size := range [][2]int{{16, 16}, {200, 50}, {50, 200}, {1000, 750}} {
+		p := &boundsPainter{width: float64(size[0]), height: float64(size[1])}
+		sceneFromGraph(g, nil).paint(p, size[0], size[1])
+		if len(p.outside) > 0 {
+			t.Errorf("%dx%d: vertices outside the image: %v", size[0], size[1], p.outside)
+		}
+	}
+}
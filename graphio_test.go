@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+// ioGraph builds a graph with n vertices, the given edges and labels, and
+// positions taken the way the editor takes them from GraphCanvas.verts.
+func ioGraph(n int, edges []testEdge, labels map[int]string, verts []fyne.Position) *Graph {
+	g := edgesGraph(n, edges)
+	for i, l := range labels {
+		g.SetLabel(i, l)
+	}
+	for i, p := range verts {
+		g.SetPos(i, Point{X: float64(p.X), Y: float64(p.Y)})
+	}
+	return g
+}
+
+func sameGraph(t *testing.T, got, want *Graph) {
+	t.Helper()
+	if got.n != want.n {
+		t.Fatalf("%d vertices, want %d", got.n, want.n)
+	}
+	for i := 0; i < want.n; i++ {
+		if got.Label(i) != want.Label(i) {
+			t.Errorf("vertex %d: label %q, want %q", i+1, got.Label(i), want.Label(i))
+		}
+		gp, gok := got.Pos(i)
+		wp, wok := want.Pos(i)
+		if gok != wok || gp != wp {
+			t.Errorf("vertex %d: pos %v (%v), want %v (%v)", i+1, gp, gok, wp, wok)
+		}
+		for j := 0; j < want.n; j++ {
+			gw, gok := got.Edge(i, j)
+			ww, wok := want.Edge(i, j)
+			if gok != wok || gw != ww {
+				t.Errorf("edge %d → %d: %v (%v), want %v (%v)", i+1, j+1, gw, gok, ww, wok)
+			}
+		}
+	}
+}
+
+func TestGraphFormatsRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		g    *Graph
+	}{
+		{"empty", ioGraph(0, nil, nil, nil)},
+		{"single vertex", ioGraph(1, nil, nil, nil)},
+		{"single labelled vertex", ioGraph(1, nil, map[int]string{0: "Москва"}, []fyne.Position{{X: 120, Y: 80}})},
+		{"negative and fractional weights", ioGraph(4, []testEdge{
+			{0, 1, -1.5}, {1, 2, 0.1}, {2, 0, 2.25}, {2, 3, -0.0001}, {3, 1, 1e6}, {0, 3, 0},
+		}, nil, nil)},
+		{"labels and positions", ioGraph(3, []testEdge{{0, 1, 3}, {1, 0, 3.5}, {1, 2, -7}}, map[int]string{
+			0: `A "quoted" label`, 2: "вершина, с запятой",
+		}, []fyne.Position{{X: 60.5, Y: 50.25}, {X: 180.3, Y: 140.7}, {X: 0, Y: 333.33}})},
+	}
+	for _, f := range graphFormats {
+		for _, c := range cases {
+			t.Run(f.Key+"/"+c.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := f.Write(&buf, c.g); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+				got, err := f.Read(&buf)
+				if err != nil {
+					t.Fatalf("read: %v\n%s", err, buf.String())
+				}
+				sameGraph(t, got, c.g)
+			})
+		}
+	}
+}
+
+func TestReadDOTWeightAttributes(t *testing.T) {
+	g, err := readDOT(strings.NewReader(`digraph {
+		a -> b [w="-2.5", weight=3, label="x"];
+		b -> c [weight=4, label="9"];
+		c -> a [label="0,5"];
+		a -> c;
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []testEdge{{0, 1, -2.5}, {1, 2, 4}, {2, 0, 0.5}, {0, 2, 1}} {
+		if w, ok := g.Edge(e.u, e.v); !ok || w != e.w {
+			t.Errorf("edge %d → %d = %v (%v), want %v", e.u+1, e.v+1, w, ok, e.w)
+		}
+	}
+}
+
+func TestGraphFormatsParseErrors(t *testing.T) {
+	cases := []struct {
+		name, format, input, want string
+	}{
+		{"dot unexpected char", "dot", "digraph { a -> b; $ }", "неожиданный символ"},
+		{"dot unclosed string", "dot", `digraph { a [label="x]; }`, "незакрытая строка"},
+		{"dot unclosed comment", "dot", "digraph { /* a -> b }", "незакрытый комментарий"},
+		{"dot not a graph", "dot", "a -> b;", "ожидалось digraph или graph"},
+		{"dot missing brace", "dot", "digraph { a -> b;", "нет закрывающей }"},
+		{"dot missing edge target", "dot", "digraph { a -> ; }", "ожидался идентификатор"},
+		{"dot duplicate edge", "dot", "digraph { a -> b [w=1]; a -> b [w=2]; }", "повторяется"},
+		{"dot undirected duplicate", "dot", "graph { a -- b; b -- a; }", "повторяется"},
+		{"dot loop", "dot", "digraph { a -> a; }", "петля"},
+
+		{"graphml bad xml", "graphml", "<graphml><graph>", "GraphML"},
+		{"graphml no graph", "graphml", "<graphml></graphml>", "нет элемента <graph>"},
+		{"graphml unknown node", "graphml", `<graphml><graph edgedefault="directed">
+			<node id="a"/><edge source="a" target="b"/></graph></graphml>`, `неизвестная вершина "b"`},
+		{"graphml missing target", "graphml", `<graphml><graph edgedefault="directed">
+			<node id="a"/><edge source="a"/></graph></graphml>`, "нет начала или конца"},
+		{"graphml duplicate edge", "graphml", `<graphml><graph edgedefault="directed">
+			<node id="a"/><node id="b"/><edge source="a" target="b"/><edge source="a" target="b"/></graph></graphml>`, "повторяется"},
+		{"graphml duplicate node", "graphml", `<graphml><graph><node id="a"/><node id="a"/></graph></graphml>`, "объявлена дважды"},
+		{"graphml bad weight", "graphml", `<graphml><key id="d0" for="edge" attr.name="weight"/><graph edgedefault="directed">
+			<node id="a"/><node id="b"/><edge source="a" target="b"><data key="d0">abc</data></edge></graph></graphml>`, "некорректный вес"},
+
+		{"json bad syntax", "json", `{"nodes": [`, "JSON"},
+		{"json unknown node", "json", `{"directed": true, "nodes": [{"id": 1}], "links": [{"source": 1, "target": 2}]}`, `неизвестная вершина "2"`},
+		{"json missing source", "json", `{"nodes": [{"id": 1}], "links": [{"target": 1}]}`, "некорректные source/target"},
+		{"json duplicate edge", "json", `{"nodes": [{"id": "a"}, {"id": "b"}], "links": [{"source": "a", "target": "b"}, {"source": "a", "target": "b", "weight": 2}]}`, "повторяется"},
+		{"json undirected duplicate", "json", `{"directed": false, "nodes": [{"id": 1}, {"id": 2}], "links": [{"source": 1, "target": 2}, {"source": 2, "target": 1}]}`, "повторяется"},
+		{"json duplicate node", "json", `{"nodes": [{"id": 1}, {"id": 1}], "links": []}`, "объявлена дважды"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, ok := graphFormatFor(c.format)
+			if !ok {
+				t.Fatalf("no format %q", c.format)
+			}
+			g, err := f.Read(strings.NewReader(c.input))
+			if err == nil {
+				t.Fatalf("no error, got a graph with %d vertices", g.n)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("error %q does not mention %q", err, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// testEdge is an arc u → v (0-based) of a test fixture.
+type testEdge struct {
+	u, v int
+	w    float64
+}
+
+// edgesGraph builds a graph with n vertices and the given arcs.
+func edgesGraph(n int, edges []testEdge) *Graph {
+	g := NewGraph()
+	g.Resize(n)
+	for _, e := range edges {
+		g.SetEdge(e.u, e.v, e.w, false)
+	}
+	return g
+}
+
+// randomGraph builds a graph with n vertices where every arc is present with
+// probability p and weighs weight().
+func randomGraph(rng *rand.Rand, n int, p float64, weight func() float64) *Graph {
+	var edges []testEdge
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && rng.Float64() < p {
+				edges = append(edges, testEdge{i, j, weight()})
+			}
+		}
+	}
+	return edgesGraph(n, edges)
+}
+
+// matrixGraph reads a graph the way a CSV matrix or the matrix editor does,
+// so the exact arithmetics see the cell text.
+func matrixGraph(t *testing.T, rows ...string) *Graph {
+	t.Helper()
+	var cells [][]string
+	for _, r := range rows {
+		cells = append(cells, strings.Split(r, ";"))
+	}
+	g, err := graphFromMatrixRows(cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
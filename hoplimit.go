@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Hop-limited shortest paths ----------------
+
+// hopLimited holds, for every pair, the shortest walk with at most K edges.
+// With negative cycles such walks are still well defined, they just may
+// repeat vertices.
+type hopLimited struct {
+	K     int
+	dist  Matrix[float64]
+	reach Matrix[bool]
+	paths [][][]int // 1-based walks, nil when there is none within K edges
+}
+
+// hopLimitedPaths runs a K-round Bellman–Ford from every source: round r
+// relaxes only from the values of round r-1, so after it every vertex holds
+// the best walk of at most r edges. Rounds stop early once nothing changes.
+func hopLimitedPaths(g *Graph, k int, st *SolverStats) hopLimited {
+	n := g.n
+	out := g.arcs()
+	h := hopLimited{K: k, dist: NewMatrix(n, 0.0), reach: NewMatrix(n, false), paths: make([][][]int, n)}
+	for s := 0; s < n; s++ {
+		d := make([]float64, n)
+		ok := make([]bool, n)
+		ok[s] = true
+		// via[r-1][v] is the vertex before v on the best walk of at most r
+		// edges, -1 when that walk is the one of round r-1
+		var via [][]int
+		for r := 1; r <= k; r++ {
+			nd := append([]float64(nil), d...)
+			nok := append([]bool(nil), ok...)
+			step := make([]int, n)
+			for v := range step {
+				step[v] = -1
+			}
+			changed := false
+			for u := 0; u < n; u++ {
+				if !ok[u] {
+					continue
+				}
+				for _, e := range out[u] {
+					st.relax()
+					if cand := d[u] + e.w; !nok[e.to] || cand < nd[e.to] {
+						st.improve()
+						nd[e.to], nok[e.to], step[e.to] = cand, true, u
+						changed = true
+					}
+				}
+			}
+			st.iterate()
+			if !changed {
+				break
+			}
+			d, ok = nd, nok
+			via = append(via, step)
+		}
+		h.paths[s] = make([][]int, n)
+		for t := 0; t < n; t++ {
+			if !ok[t] {
+				continue
+			}
+			h.dist.Set(s, t, d[t])
+			h.reach.Set(s, t, true)
+			walk := []int{t + 1}
+			v := t
+			for r := len(via); r > 0; r-- {
+				if u := via[r-1][v]; u >= 0 {
+					walk = append(walk, u+1)
+					v = u
+				}
+			}
+			for l, r := 0, len(walk)-1; l < r; l, r = l+1, r-1 {
+				walk[l], walk[r] = walk[r], walk[l]
+			}
+			h.paths[s][t] = walk
+		}
+	}
+	return h
+}
+
+func (h hopLimited) Dist(i, j int) (float64, bool) { return h.dist.At(i, j), h.reach.At(i, j) }
+
+func (h hopLimited) Path(i, j int) []int { return h.paths[i][j] }
+
+// hopPanel compares hop-limited distances with unconstrained ones.
+type hopPanel struct {
+	box   fyne.CanvasObject
+	k     *widget.Entry
+	info  *widget.Label
+	table *widget.Table
+
+	rows [][hopCols]string
+
+	onChange func() // k was edited
+}
+
+const (
+	hopColPair = iota
+	hopColLimited
+	hopColLimitedPath
+	hopColFree
+	hopColFreePath
+	hopCols
+)
+
+func newHopPanel() *hopPanel {
+	p := &hopPanel{k: widget.NewEntry(), info: widget.NewLabel("")}
+	p.info.Wrapping = fyne.TextWrapWord
+	p.k.SetText("2")
+	p.k.OnChanged = func(string) {
+		if p.onChange != nil {
+			p.onChange()
+		}
+	}
+	p.table = widget.NewTable(
+		func() (int, int) { return len(p.rows), hopCols },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			txt := ""
+			if id.Row < len(p.rows) && id.Col < hopCols {
+				txt = p.rows[id.Row][id.Col]
+			}
+			co.(*widget.Label).SetText(txt)
+		},
+	)
+	p.table.ShowHeaderRow = true
+	p.table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	p.table.UpdateHeader = func(id widget.TableCellID, co fyne.CanvasObject) {
+		titles := [hopCols]string{"Пара", "≤ k рёбер", "Путь", "Без ограничения", "Путь"}
+		if id.Col >= 0 && id.Col < hopCols {
+			co.(*widget.Label).SetText(titles[id.Col])
+		}
+	}
+	for col, width := range [hopCols]float32{80, 100, 140, 130, 140} {
+		p.table.SetColumnWidth(col, width)
+	}
+	top := container.NewVBox(container.NewHBox(widget.NewLabel("Максимум рёбер k:"), sizedEntry(p.k, 60)), p.info)
+	p.box = container.NewBorder(top, nil, nil, nil, p.table)
+	return p
+}
+
+// K returns the entered edge limit, or an error for anything but a positive
+// integer.
+func (p *hopPanel) K() (int, error) {
+	k, err := strconv.Atoi(strings.TrimSpace(p.k.Text))
+	if err != nil || k < 1 {
+		return 0, fmt.Errorf("k должно быть целым числом не меньше 1")
+	}
+	return k, nil
+}
+
+// Update recomputes the limited paths of g; free is the unconstrained result
+// and freeErr its error, shown instead of the free columns.
+func (p *hopPanel) Update(g *Graph, free *APSPResult, freeErr error) {
+	p.rows = nil
+	defer p.table.Refresh()
+	k, err := p.K()
+	if err != nil {
+		p.info.SetText(err.Error())
+		return
+	}
+	h := hopLimitedPaths(g, k, nil)
+	longer, lost := 0, 0
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if i == j {
+				continue
+			}
+			d, ok := h.Dist(i, j)
+			row := [hopCols]string{hopColPair: vertexName(i) + " → " + vertexName(j), hopColLimited: distText(d, ok), hopColLimitedPath: "пути нет"}
+			if ok {
+				row[hopColLimitedPath] = joinPathInts(h.Path(i, j))
+			}
+			switch {
+			case freeErr != nil:
+				row[hopColFree], row[hopColFreePath] = "—", "—"
+			default:
+				fd, fok := free.Dist(i, j)
+				row[hopColFree], row[hopColFreePath] = distText(fd, fok), "пути нет"
+				if fok {
+					row[hopColFreePath] = joinPathInts(free.Path(i, j))
+				}
+				switch {
+				case fok && !ok:
+					lost++
+				case ok && !sameDist(d, ok, fd, fok):
+					longer++
+				}
+			}
+			p.rows = append(p.rows, row)
+		}
+	}
+	if freeErr != nil {
+		p.info.SetText(fmt.Sprintf("Без ограничения: %v", freeErr))
+		return
+	}
+	p.info.SetText(fmt.Sprintf("При k = %d путь длиннее у %d пар, недостижимы %d пар", k, longer, lost))
+}
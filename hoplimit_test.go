@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// hopReference is the textbook recurrence: the best walk of at most r edges
+// either has at most r-1 edges or extends one of them by an edge.
+func hopReference(g *Graph, k int) [][]float64 {
+	n := g.n
+	d := make([][]float64, n)
+	for s := range d {
+		d[s] = make([]float64, n)
+		for v := range d[s] {
+			d[s][v] = math.Inf(1)
+		}
+		d[s][s] = 0
+	}
+	for r := 0; r < k; r++ {
+		next := make([][]float64, n)
+		for s := range d {
+			next[s] = append([]float64(nil), d[s]...)
+			for u := 0; u < n; u++ {
+				for v := 0; v < n; v++ {
+					if w, ok := g.Edge(u, v); ok && d[s][u]+w < next[s][v] {
+						next[s][v] = d[s][u] + w
+					}
+				}
+			}
+		}
+		d = next
+	}
+	return d
+}
+
+// checkHopPaths checks every walk of h: it joins the right ends, has at most
+// K edges, uses existing edges and is as long as the reported distance.
+func checkHopPaths(t *testing.T, g *Graph, h hopLimited) {
+	t.Helper()
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			d, ok := h.Dist(i, j)
+			path := h.Path(i, j)
+			if !ok {
+				if path != nil {
+					t.Fatalf("k=%d: %d → %d unreachable but has path %v", h.K, i+1, j+1, path)
+				}
+				continue
+			}
+			if len(path) == 0 || path[0] != i+1 || path[len(path)-1] != j+1 || len(path)-1 > h.K {
+				t.Fatalf("k=%d: path %d → %d = %v", h.K, i+1, j+1, path)
+			}
+			var sum float64
+			for p := 0; p+1 < len(path); p++ {
+				w, ok := g.Edge(path[p]-1, path[p+1]-1)
+				if !ok {
+					t.Fatalf("k=%d: path %d → %d = %v uses a missing edge", h.K, i+1, j+1, path)
+				}
+				sum += w
+			}
+			if sum != d {
+				t.Fatalf("k=%d: path %d → %d = %v has length %v, reported %v", h.K, i+1, j+1, path, sum, d)
+			}
+		}
+	}
+}
+
+func TestHopLimitedSmall(t *testing.T) {
+	// 1 → 3 directly costs 5, through 2 it costs 2
+	g := matrixGraph(t, "0;1;5", ";0;1", ";;0")
+	cases := []struct {
+		k    int
+		dist float64
+		path []int
+	}{
+		{1, 5, []int{1, 3}},
+		{2, 2, []int{1, 2, 3}},
+		{5, 2, []int{1, 2, 3}},
+	}
+	for _, c := range cases {
+		h := hopLimitedPaths(g, c.k, nil)
+		checkHopPaths(t, g, h)
+		d, ok := h.Dist(0, 2)
+		if !ok || d != c.dist || !slices.Equal(h.Path(0, 2), c.path) {
+			t.Errorf("k=%d: 1 → 3 = %v (%v) via %v, want %v via %v", c.k, d, ok, h.Path(0, 2), c.dist, c.path)
+		}
+	}
+
+	h := hopLimitedPaths(g, 0, nil)
+	checkHopPaths(t, g, h)
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if d, ok := h.Dist(i, j); ok != (i == j) || d != 0 {
+				t.Errorf("k=0: %d → %d = %v (%v)", i+1, j+1, d, ok)
+			}
+		}
+	}
+}
+
+func TestHopLimitedRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(49))
+	compared := 0
+	for round := 0; round < 100; round++ {
+		n := 1 + rng.Intn(7)
+		g := randomGraph(rng, n, 0.4, func() float64 { return float64(rng.Intn(12) - 3) })
+		free, freeErr := solveFloyd(g, nil)
+		if freeErr == nil {
+			compared++
+		}
+		for _, k := range []int{0, 1, 2, n - 1, n + 2} {
+			if k < 0 {
+				continue
+			}
+			h := hopLimitedPaths(g, k, nil)
+			checkHopPaths(t, g, h)
+			ref := hopReference(g, k)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					d, ok := h.Dist(i, j)
+					if want := ref[i][j]; ok != !math.IsInf(want, 1) || (ok && d != want) {
+						t.Fatalf("round %d, k=%d: %d → %d = %v (%v), want %v", round, k, i+1, j+1, d, ok, want)
+					}
+					// with no negative cycle n-1 edges are enough for every shortest path
+					if k >= n-1 && freeErr == nil {
+						if fd, fok := free.Dist(i, j); ok != fok || d != fd {
+							t.Fatalf("round %d, k=%d: %d → %d = %v (%v), Floyd %v (%v)", round, k, i+1, j+1, d, ok, fd, fok)
+						}
+					}
+				}
+			}
+		}
+	}
+	if compared < 20 {
+		t.Fatalf("only %d graphs without negative cycles were compared with Floyd", compared)
+	}
+}
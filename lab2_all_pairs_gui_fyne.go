@@ -1,10 +1,12 @@
 package main
 
 import (
-	"encoding/csv"
+	"container/heap"
 	"fmt"
 	"image/color"
 	"math"
+	"math/big"
+	"os"
 	"strconv"
 	"strings"
 
@@ -18,179 +20,289 @@ import (
 )
 
 const (
-	INF         = 1e18
-	MaxVertices = 10
+	MaxVertices = 10 // limit for the matrix UI and the editor; files may be larger
 	vertexR     = float32(18)
 )
 
 // ---------------- Core graph model ----------------
 
+// Point is a vertex position in editor coordinates (y grows downwards).
+type Point struct{ X, Y float64 }
+
+// Graph is a weighted digraph stored as a dense matrix. Edge presence is kept
+// separately from the weights, so every finite weight is a valid edge.
 type Graph struct {
 	n       int
-	adj     [][]float64
-	negEdge bool
+	adj     Matrix[float64] // weight of i → j, 0 where there is no edge
+	has     Matrix[bool]    // has[i][j] reports whether edge i → j exists
+	exact   Matrix[string]  // weight as typed when float64 cannot hold it ("1/3"), else ""
+	stats   EdgeStats       // kept in step with adj and has by every mutation
+	version uint64          // bumped on every mutation, keys cached results
+
+	// presentation data; does not affect distances or the version
+	labels []string
+	pos    []Point
+	hasPos []bool
 }
 
 func NewGraph() *Graph { return &Graph{} }
 
+func (g *Graph) Version() uint64 { return g.version }
+
+// assign replaces g's contents with src, keeping g's identity for caches and editors.
+func (g *Graph) assign(src *Graph) {
+	ver := g.version
+	*g = *src
+	g.version = ver + 1
+}
+
+// Label returns the vertex label, or its 1-based number when unlabeled.
+func (g *Graph) Label(i int) string {
+	if g.labels[i] != "" {
+		return g.labels[i]
+	}
+	return vertexName(i)
+}
+
+func (g *Graph) SetLabel(i int, s string) {
+	if i >= 0 && i < g.n {
+		g.labels[i] = s
+	}
+}
+
+// Pos returns the stored layout position of vertex i, if any.
+func (g *Graph) Pos(i int) (Point, bool) { return g.pos[i], g.hasPos[i] }
+
+func (g *Graph) SetPos(i int, p Point) {
+	if i >= 0 && i < g.n {
+		g.pos[i], g.hasPos[i] = p, true
+	}
+}
+
 func (g *Graph) Resize(n int) {
 	if n < 0 {
 		n = 0
 	}
-	if n > MaxVertices {
-		n = MaxVertices
-	}
-	old := g.adj
+	g.version++
 	g.n = n
-	g.adj = make([][]float64, n)
-	for i := 0; i < n; i++ {
-		g.adj[i] = make([]float64, n)
-		for j := 0; j < n; j++ {
-			if i == j {
-				g.adj[i][j] = 0
-			} else {
-				g.adj[i][j] = INF
-			}
-		}
-	}
-	for i := 0; i < n && i < len(old); i++ {
-		for j := 0; j < n && j < len(old); j++ {
-			g.adj[i][j] = old[i][j]
-		}
-	}
-	g.negEdge = false
-	for i := 0; i < g.n; i++ {
-		for j := 0; j < g.n; j++ {
-			if i != j && g.adj[i][j] < 0 && g.adj[i][j] < INF/2 {
-				g.negEdge = true
-			}
-		}
-	}
+	g.adj = g.adj.Resized(n, 0)
+	g.has = g.has.Resized(n, false)
+	g.exact = g.exact.Resized(n, "")
+	labels, pos, hasPos := make([]string, n), make([]Point, n), make([]bool, n)
+	copy(labels, g.labels)
+	copy(pos, g.pos)
+	copy(hasPos, g.hasPos)
+	g.labels, g.pos, g.hasPos = labels, pos, hasPos
+	g.recountEdges()
 }
 
-func (g *Graph) SetEdge(i, j int, val float64, isInf bool) {
+// SetEdge sets the weight of i → j, or removes the edge when absent is true.
+// Loops are ignored. Weights are expected to have passed checkWeight.
+func (g *Graph) SetEdge(i, j int, val float64, absent bool) {
 	if i < 0 || j < 0 || i >= g.n || j >= g.n {
 		return
 	}
+	g.version++
+	g.exact.Set(i, j, "")
 	if i == j {
-		g.adj[i][j] = 0
 		return
 	}
-	if isInf {
-		g.adj[i][j] = INF
-	} else {
-		g.adj[i][j] = val
-		if val < 0 {
-			g.negEdge = true
-		}
+	old, had := g.Edge(i, j)
+	if absent {
+		val = 0
 	}
+	g.adj.Set(i, j, val)
+	g.has.Set(i, j, !absent)
+	g.edgeChanged(old, had, val, !absent)
 }
 
-func (g *Graph) HasNegativeEdge() bool { return g.negEdge }
+// EdgeStats summarizes the edges of a graph; Min and Max are meaningful only
+// when Edges > 0.
+type EdgeStats struct {
+	Edges    int
+	Negative int
+	Min, Max float64
+}
 
-func (g *Graph) FloydWarshall() (dist [][]float64, pred [][]int, negCycle bool) {
-	n := g.n
-	dist = make([][]float64, n)
-	pred = make([][]int, n)
-	for i := 0; i < n; i++ {
-		dist[i] = make([]float64, n)
-		pred[i] = make([]int, n)
-		for j := 0; j < n; j++ {
-			dist[i][j] = g.adj[i][j]
-			if i == j && dist[i][j] == 0 {
-				pred[i][j] = i
-			} else if dist[i][j] < INF/2 {
-				pred[i][j] = i
-			} else {
-				pred[i][j] = -1
-			}
-		}
+func (s EdgeStats) String() string {
+	if s.Edges == 0 {
+		return "дуг нет"
 	}
-	for k := 0; k < n; k++ {
-		for i := 0; i < n; i++ {
-			if dist[i][k] >= INF/2 {
-				continue
-			}
-			dik := dist[i][k]
-			for j := 0; j < n; j++ {
-				if dist[k][j] >= INF/2 {
-					continue
-				}
-				cand := dik + dist[k][j]
-				if cand < dist[i][j] {
-					dist[i][j] = cand
-					pred[i][j] = pred[k][j]
-				}
-			}
+	return fmt.Sprintf("дуг %d, отрицательных %d, веса от %g до %g", s.Edges, s.Negative, s.Min, s.Max)
+}
+
+func (g *Graph) EdgeStats() EdgeStats { return g.stats }
+
+func (g *Graph) HasNegativeEdge() bool { return g.stats.Negative > 0 }
+
+// edgeChanged updates the statistics after one edge went from (old, had) to
+// (w, has) in O(1); only dropping the current minimum or maximum needs a
+// rescan of the matrix.
+func (g *Graph) edgeChanged(old float64, had bool, w float64, has bool) {
+	s := &g.stats
+	if had && (old == s.Min || old == s.Max) {
+		g.recountEdges()
+		return
+	}
+	if had {
+		s.Edges--
+		if old < 0 {
+			s.Negative--
 		}
 	}
-	for i := 0; i < n; i++ {
-		if dist[i][i] < 0 {
-			return dist, pred, true
+	if has {
+		s.add(w)
+	}
+}
+
+// recountEdges recomputes the statistics from scratch.
+func (g *Graph) recountEdges() {
+	g.stats = EdgeStats{}
+	for k, ok := range g.has.data {
+		if ok {
+			g.stats.add(g.adj.data[k])
 		}
 	}
-	return dist, pred, false
 }
 
-func reconstructPathPred(pred [][]int, i, j int) []int {
-	if pred[i][j] == -1 {
-		return nil
+func (s *EdgeStats) add(w float64) {
+	if s.Edges == 0 {
+		s.Min, s.Max = w, w
 	}
-	path := []int{j}
-	cur := j
-	for cur != i {
-		cur = pred[i][cur]
-		if cur == -1 {
-			return nil
-		}
-		path = append(path, cur)
+	s.Edges++
+	if w < 0 {
+		s.Negative++
 	}
-	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
-		path[l], path[r] = path[r], path[l]
+	s.Min, s.Max = math.Min(s.Min, w), math.Max(s.Max, w)
+}
+
+// Edge returns the weight of i → j and whether the edge exists.
+func (g *Graph) Edge(i, j int) (float64, bool) { return g.adj.At(i, j), g.has.At(i, j) }
+
+func (g *Graph) HasEdge(i, j int) bool { return g.has.At(i, j) }
+
+// maxWeight bounds edge weights so no path length can overflow: a simple
+// path has fewer than 2^20 edges in any graph whose dense matrix fits in
+// memory, so its length stays finite.
+const maxWeight = math.MaxFloat64 / (1 << 20)
+
+// checkWeight rejects weights that are not finite numbers or whose path sums
+// could overflow.
+func checkWeight(w float64) error {
+	switch {
+	case math.IsNaN(w) || math.IsInf(w, 0):
+		return fmt.Errorf("некорректное число")
+	case math.Abs(w) > maxWeight:
+		return fmt.Errorf("вес %g слишком велик по модулю (допускается до %.3g)", w, maxWeight)
 	}
-	for k := range path {
-		path[k]++
+	return nil
+}
+
+// SetExactText keeps the cell text s an edge weight was parsed from, for the
+// exact arithmetics; call it right after SetEdge. The float solvers do not
+// see it, so the version is not bumped.
+func (g *Graph) SetExactText(i, j int, s string) {
+	if w, ok := g.Edge(i, j); ok {
+		g.exact.Set(i, j, exactText(s, w))
 	}
-	return path
 }
 
-func (g *Graph) dijkstraFrom(s int) ([]float64, []int) {
-	n := g.n
+// WeightText is the exact decimal or fraction form of the weight of i → j.
+func (g *Graph) WeightText(i, j int) string {
+	if s := g.exact.At(i, j); s != "" {
+		return s
+	}
+	return formatWeight(g.adj.At(i, j))
+}
+
+// FloydWarshall computes all-pairs shortest distances with the blocked
+// kernel; st may be nil.
+func (g *Graph) FloydWarshall(st *SolverStats) (dist Matrix[float64], pred Matrix[int], negCycle bool) {
+	return g.Closure(pathSemirings[0], st)
+}
+
+type arc struct {
+	to int
+	w  float64
+}
+
+// arcs lists outgoing edges per vertex so sparse graphs are scanned in O(m).
+func (g *Graph) arcs() [][]arc {
+	out := make([][]arc, g.n)
+	for v := 0; v < g.n; v++ {
+		for u := 0; u < g.n; u++ {
+			if g.has.At(v, u) {
+				out[v] = append(out[v], arc{to: u, w: g.adj.At(v, u)})
+			}
+		}
+	}
+	return out
+}
+
+// dijkstraFrom computes single-source distances from s; st may be nil.
+func (g *Graph) dijkstraFrom(s int, st *SolverStats) ([]float64, []int) {
+	return dijkstraArcs(g.arcs(), s, st)
+}
+
+// dijkstraArcs is Dijkstra with a binary heap and lazy deletion.
+func dijkstraArcs(out [][]arc, s int, st *SolverStats) ([]float64, []int) {
+	n := len(out)
 	dist := make([]float64, n)
 	prev := make([]int, n)
-	used := make([]bool, n)
-	for i := 0; i < n; i++ {
-		dist[i] = INF
+	for i := range prev {
 		prev[i] = -1
 	}
-	dist[s] = 0
-	for it := 0; it < n; it++ {
-		v := -1
-		best := INF
-		for i := 0; i < n; i++ {
-			if !used[i] && dist[i] < best {
-				best = dist[i]
-				v = i
-			}
-		}
-		if v == -1 {
-			break
+	prev[s] = s
+	pq := &distHeap{st: st}
+	heap.Push(pq, heapItem{v: s, d: 0})
+	st.heapOp()
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(heapItem)
+		st.heapOp()
+		st.compare(1)
+		if it.d > dist[it.v] {
+			continue
 		}
-		used[v] = true
-		for u := 0; u < n; u++ {
-			w := g.adj[v][u]
-			if w >= INF/2 {
-				continue
-			}
-			if dist[v]+w < dist[u] {
-				dist[u] = dist[v] + w
-				prev[u] = v
+		v := it.v
+		for _, e := range out[v] {
+			st.iterate()
+			st.relax()
+			if cand := dist[v] + e.w; prev[e.to] < 0 || cand < dist[e.to] {
+				st.improve()
+				dist[e.to] = cand
+				prev[e.to] = v
+				heap.Push(pq, heapItem{v: e.to, d: cand})
+				st.heapOp()
 			}
 		}
 	}
 	return dist, prev
 }
 
+type heapItem struct {
+	v int
+	d float64
+}
+
+// distHeap is a min-heap on tentative distance; comparisons are counted in st.
+type distHeap struct {
+	items []heapItem
+	st    *SolverStats
+}
+
+func (h *distHeap) Len() int { return len(h.items) }
+func (h *distHeap) Less(i, j int) bool {
+	h.st.compare(1)
+	return h.items[i].d < h.items[j].d
+}
+func (h *distHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *distHeap) Push(x any)    { h.items = append(h.items, x.(heapItem)) }
+func (h *distHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
 func reconstructFromPrev(prev []int, s, t int) []int {
 	if s == t {
 		return []int{s + 1}
@@ -221,29 +333,47 @@ func reconstructFromPrev(prev []int, s, t int) []int {
 
 // ---------------- UI helpers ----------------
 
-func infOrFloat(s string) (float64, bool, error) {
+// infOrFloat parses a matrix cell; an empty cell, "∞" or "inf" means no edge
+// and is reported as absent rather than as a sentinel weight. Fractions such
+// as 1/3 are accepted and rounded to float64.
+func infOrFloat(s string) (w float64, absent bool, err error) {
 	s = strings.TrimSpace(s)
-	if s == "" {
-		return INF, true, nil
-	}
-	if s == "∞" || strings.EqualFold(s, "inf") {
-		return INF, true, nil
+	if s == "" || s == "∞" || strings.EqualFold(s, "inf") {
+		return 0, true, nil
 	}
 	v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
-	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+	if err != nil && strings.Contains(s, "/") {
+		if r, ok := new(big.Rat).SetString(s); ok {
+			v, _ = r.Float64()
+			err = nil
+		}
+	}
+	if err != nil {
 		return 0, false, fmt.Errorf("некорректное число")
 	}
+	if err := checkWeight(v); err != nil {
+		return 0, false, err
+	}
 	return v, false, nil
 }
 
-func floatToCell(v float64, i, j int) string {
+// floatToCell formats cell (i, j) of g's matrix, "" when there is no edge.
+func floatToCell(g *Graph, i, j int) string {
 	if i == j {
 		return "0"
 	}
-	if v >= INF/2 {
+	if !g.HasEdge(i, j) {
 		return ""
 	}
-	return strconv.FormatFloat(v, 'g', -1, 64)
+	return g.WeightText(i, j)
+}
+
+func vertexName(i int) string { return strconv.Itoa(i + 1) }
+
+// circlePos is the default layout of vertex i out of n in the editor.
+func circlePos(i, n int) Point {
+	a := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+	return Point{X: 250 + 140*math.Cos(a), Y: 180 + 140*math.Sin(a)}
 }
 
 func joinPathInts(p []int) string {
@@ -267,22 +397,36 @@ type edgeRec struct {
 type GraphCanvas struct {
 	widget.BaseWidget
 	verts            []fyne.Position
+	labels           []string // parallel to verts, "" = number only
 	edges            []edgeRec
 	mode             string // move|addv|adde|delete
 	pending          int    // -1 none else start vertex index
 	dragIdx          int    // -1 none
 	pick             string
 	startIdx, endIdx int
+	selected         int // -1 none, vertex picked in move mode
 	highlightPairs   map[[2]int]bool
+	showComps        bool
+	comp             []int              // strongly connected component per vertex when showComps
+	roles            []vertexRole       // center/periphery outlines, nil for none
+	scale            *centralityScale   // betweenness sizes, nil for the normal look
+	badges           []string           // distance per vertex of a path tree, nil for none
+	marks            map[int]vertexMark // path-finder waypoints and blocked vertices
+	blocked          map[[2]int]bool    // edges the path finder must avoid
 
 	askWeight func(u, v int, done func(w float64, ok bool))
 	onChange  func()
+	onSelect  func(v int)
+	onLayout  func() // a vertex was dragged
+	onMarks   func() // a constraint was set by right-click
 }
 
 func NewGraphCanvas() *GraphCanvas {
-	gc := &GraphCanvas{mode: "move", pending: -1, dragIdx: -1, startIdx: -1, endIdx: -1}
+	gc := &GraphCanvas{mode: "move", pending: -1, dragIdx: -1, startIdx: -1, endIdx: -1, selected: -1}
 	gc.ExtendBaseWidget(gc)
 	gc.highlightPairs = make(map[[2]int]bool)
+	gc.marks = make(map[int]vertexMark)
+	gc.blocked = make(map[[2]int]bool)
 	return gc
 }
 
@@ -306,52 +450,118 @@ func (r *graphRenderer) Refresh() {
 	for _, e := range r.gc.edges {
 		p1 := r.gc.verts[e.U]
 		p2 := r.gc.verts[e.V]
-		ln := canvas.NewLine(color.NRGBA{R: 68, G: 68, B: 85, A: 255})
+		ln := canvas.NewLine(edgeColor)
 		ln.StrokeWidth = 2
 		if r.gc.highlightPairs[[2]int{e.U, e.V}] {
-			ln.StrokeColor = color.NRGBA{R: 200, G: 0, B: 0, A: 255}
+			ln.StrokeColor = pathColor
 			ln.StrokeWidth = 3
 		}
+		if r.gc.blocked[[2]int{e.U, e.V}] {
+			ln.StrokeColor = blockedColor
+		}
+		ln.StrokeWidth *= float32(r.gc.scale.edgeK(e.U, e.V))
 		ln.Position1 = p1
 		ln.Position2 = p2
 		mx := (p1.X + p2.X) / 2
 		my := (p1.Y + p2.Y) / 2
-		txt := canvas.NewText(strconv.FormatFloat(e.W, 'g', -1, 64), color.NRGBA{A: 255})
+		txt := canvas.NewText(strconv.FormatFloat(e.W, 'g', -1, 64), textColor)
 		txt.TextSize = 12
 		txt.Move(fyne.NewPos(mx-8, my-16))
 		objs = append(objs, ln, txt)
 	}
 	// vertices
 	for i, p := range r.gc.verts {
-		fill := color.NRGBA{R: 232, G: 240, B: 254, A: 255}
-		if i == r.gc.startIdx && i == r.gc.endIdx {
-			fill = color.NRGBA{R: 253, G: 244, B: 191, A: 255}
-		} else if i == r.gc.startIdx {
-			fill = color.NRGBA{R: 209, G: 250, B: 223, A: 255}
-		} else if i == r.gc.endIdx {
-			fill = color.NRGBA{R: 255, G: 232, B: 232, A: 255}
-		}
+		stroke, sw := vertexOutline(i, r.gc.roles)
+		fill, stroke, sw := r.gc.marks[i].style(vertexFill(i, r.gc.startIdx, r.gc.endIdx, r.gc.comp), stroke, sw)
 		c := canvas.NewCircle(fill)
-		c.StrokeColor = color.NRGBA{R: 86, G: 103, B: 119, A: 255}
-		c.StrokeWidth = 2
-		c.Resize(fyne.NewSize(vertexR*2, vertexR*2))
-		c.Move(fyne.NewPos(p.X-vertexR, p.Y-vertexR))
-		label := canvas.NewText(strconv.Itoa(i+1), color.NRGBA{A: 255})
+		c.StrokeColor = stroke
+		c.StrokeWidth = float32(sw)
+		if i == r.gc.selected {
+			c.StrokeColor = color.NRGBA{R: 37, G: 99, B: 235, A: 255}
+			c.StrokeWidth = 4
+		}
+		rad := r.gc.radius(i)
+		c.Resize(fyne.NewSize(rad*2, rad*2))
+		c.Move(fyne.NewPos(p.X-rad, p.Y-rad))
+		label := canvas.NewText(strconv.Itoa(i+1), textColor)
 		label.TextStyle = fyne.TextStyle{Bold: true}
 		label.TextSize = 12
 		label.Move(fyne.NewPos(p.X-4, p.Y-8))
 		objs = append(objs, c, label)
+		if i < len(r.gc.labels) && r.gc.labels[i] != "" {
+			name := canvas.NewText(r.gc.labels[i], labelColor)
+			name.TextSize = 11
+			name.Move(fyne.NewPos(p.X-vertexR, p.Y+rad+2))
+			objs = append(objs, name)
+		}
+		if i < len(r.gc.badges) {
+			txt := canvas.NewText(r.gc.badges[i], badgeText)
+			txt.TextSize = 11
+			size := fyne.MeasureText(txt.Text, txt.TextSize, txt.TextStyle)
+			bg := canvas.NewRectangle(badgeFill)
+			bg.CornerRadius = 4
+			bg.Resize(fyne.NewSize(size.Width+8, size.Height))
+			bg.Move(fyne.NewPos(p.X+rad*0.7, p.Y-rad-size.Height*0.6))
+			txt.Move(bg.Position().AddXY(4, 0))
+			objs = append(objs, bg, txt)
+		}
 	}
 	r.root.Objects = objs
 	r.root.Refresh()
 }
 
+// Colours shared by the canvas and the image export.
+var (
+	edgeColor    = color.NRGBA{R: 68, G: 68, B: 85, A: 255}
+	pathColor    = color.NRGBA{R: 200, G: 0, B: 0, A: 255}
+	vertexStroke = color.NRGBA{R: 86, G: 103, B: 119, A: 255}
+	textColor    = color.NRGBA{A: 255}
+	labelColor   = color.NRGBA{R: 55, G: 65, B: 81, A: 255}
+	badgeFill    = color.NRGBA{R: 254, G: 243, B: 199, A: 255}
+	badgeText    = color.NRGBA{R: 146, G: 64, B: 14, A: 255}
+)
+
+// vertexOutline returns the stroke of vertex i: thick and coloured for
+// center and periphery vertices.
+func vertexOutline(i int, roles []vertexRole) (color.NRGBA, float64) {
+	if i < len(roles) {
+		switch {
+		case roles[i]&roleCenter != 0:
+			return color.NRGBA{R: 22, G: 163, B: 74, A: 255}, 4
+		case roles[i]&rolePeriphery != 0:
+			return color.NRGBA{R: 234, G: 88, B: 12, A: 255}, 4
+		}
+	}
+	return vertexStroke, 2
+}
+
+// invalidCellColor outlines matrix cells whose text could not be applied.
+var invalidCellColor = color.NRGBA{R: 220, G: 38, B: 38, A: 255}
+
+// vertexFill colours vertex i by its component when comp is set, otherwise
+// marks the path ends.
+func vertexFill(i, start, end int, comp []int) color.NRGBA {
+	switch {
+	case i < len(comp):
+		return componentColor(comp[i])
+	case i == start && i == end:
+		return color.NRGBA{R: 253, G: 244, B: 191, A: 255}
+	case i == start:
+		return color.NRGBA{R: 209, G: 250, B: 223, A: 255}
+	case i == end:
+		return color.NRGBA{R: 255, G: 232, B: 232, A: 255}
+	}
+	return color.NRGBA{R: 232, G: 240, B: 254, A: 255}
+}
+
 // Helpers for canvas
+func (gc *GraphCanvas) radius(i int) float32 { return vertexR * float32(gc.scale.vertexK(i)) }
+
 func (gc *GraphCanvas) findVertex(pos fyne.Position) int {
 	for i, p := range gc.verts {
 		dx := p.X - pos.X
 		dy := p.Y - pos.Y
-		if dx*dx+dy*dy <= vertexR*vertexR {
+		if rad := gc.radius(i); dx*dx+dy*dy <= rad*rad {
 			return i
 		}
 	}
@@ -412,6 +622,7 @@ func (gc *GraphCanvas) Tapped(ev *fyne.PointEvent) {
 			return
 		}
 		gc.verts = append(gc.verts, ev.Position)
+		gc.labels = append(gc.labels, "")
 		gc.Refresh()
 		if gc.onChange != nil {
 			gc.onChange()
@@ -473,7 +684,12 @@ func (gc *GraphCanvas) Tapped(ev *fyne.PointEvent) {
 			return
 		}
 	default:
-		// move mode: no tap action
+		// move mode: tapping selects a vertex (or clears the selection)
+		gc.selected = gc.findVertex(ev.Position)
+		gc.Refresh()
+		if gc.onSelect != nil {
+			gc.onSelect(gc.selected)
+		}
 	}
 }
 
@@ -492,7 +708,12 @@ func (gc *GraphCanvas) Dragged(ev *fyne.DragEvent) {
 	gc.Refresh()
 }
 
-func (gc *GraphCanvas) DragEnd() { gc.dragIdx = -1 }
+func (gc *GraphCanvas) DragEnd() {
+	if gc.dragIdx != -1 && gc.onLayout != nil {
+		gc.onLayout()
+	}
+	gc.dragIdx = -1
+}
 
 func (gc *GraphCanvas) deleteVertex(vid int) {
 	out := make([]edgeRec, 0, len(gc.edges))
@@ -503,12 +724,17 @@ func (gc *GraphCanvas) deleteVertex(vid int) {
 	}
 	gc.edges = out
 	gc.verts = append(gc.verts[:vid], gc.verts[vid+1:]...)
+	gc.labels = append(gc.labels[:vid], gc.labels[vid+1:]...)
 	if gc.startIdx == vid {
 		gc.startIdx = -1
 	}
 	if gc.endIdx == vid {
 		gc.endIdx = -1
 	}
+	if gc.selected == vid {
+		gc.selected = -1
+	}
+	gc.dropVertexMarks(vid)
 	for i := range gc.edges {
 		if gc.edges[i].U > vid {
 			gc.edges[i].U--
@@ -519,34 +745,113 @@ func (gc *GraphCanvas) deleteVertex(vid int) {
 	}
 }
 
+// applyEdgeDiff pushes edge changes to the graph one by one so cached
+// results can be updated incrementally; vertex sets must already match.
+func (gc *GraphCanvas) applyEdgeDiff(cache *apspCache) {
+	g := cache.g
+	want := make(map[[2]int]float64, len(gc.edges))
+	for _, e := range gc.edges {
+		want[[2]int{e.U, e.V}] = e.W
+	}
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if i == j {
+				continue
+			}
+			w, ok := want[[2]int{i, j}]
+			cur, has := g.Edge(i, j)
+			switch {
+			case ok && (!has || w != cur):
+				cache.SetEdge(i, j, w, false)
+			case !ok && has:
+				cache.SetEdge(i, j, 0, true)
+			}
+		}
+	}
+}
+
+// storeLayout copies vertex positions and labels from the canvas into g.
+func (gc *GraphCanvas) storeLayout(g *Graph) {
+	for i := 0; i < g.n && i < len(gc.verts); i++ {
+		g.SetPos(i, Point{X: float64(gc.verts[i].X), Y: float64(gc.verts[i].Y)})
+		g.SetLabel(i, gc.labels[i])
+	}
+}
+
+// syncToGraph replaces g's edges with the canvas ones (Resize bumps the version).
 func (gc *GraphCanvas) syncToGraph(g *Graph) {
 	n := len(gc.verts)
 	g.Resize(n)
+	gc.storeLayout(g)
+	g.adj = NewMatrix(n, 0.0)
+	g.has = NewMatrix(n, false)
+	g.exact = NewMatrix(n, "")
+	for _, e := range gc.edges {
+		g.adj.Set(e.U, e.V, e.W)
+		g.has.Set(e.U, e.V, true)
+	}
+	g.recountEdges()
+}
+
+// loadFromGraph rebuilds the canvas edges from g, keeping positions of
+// existing vertices; new ones use g's layout or are placed on a circle.
+func (gc *GraphCanvas) loadFromGraph(g *Graph) {
+	n := g.n
+	if len(gc.verts) > n {
+		gc.verts = gc.verts[:n]
+	}
+	for i := len(gc.verts); i < n; i++ {
+		p, ok := g.Pos(i)
+		if !ok {
+			p = circlePos(i, n)
+		}
+		gc.verts = append(gc.verts, fyne.NewPos(float32(p.X), float32(p.Y)))
+	}
+	gc.labels = append(gc.labels[:0], g.labels...)
+	gc.edges = gc.edges[:0]
 	for i := 0; i < n; i++ {
 		for j := 0; j < n; j++ {
-			if i != j {
-				g.adj[i][j] = INF
-			} else {
-				g.adj[i][j] = 0
+			if w, ok := g.Edge(i, j); ok {
+				gc.edges = append(gc.edges, edgeRec{U: i, V: j, W: w})
 			}
 		}
 	}
-	g.negEdge = false
-	for _, e := range gc.edges {
-		g.adj[e.U][e.V] = e.W
-		if e.W < 0 {
-			g.negEdge = true
+	for _, p := range []*int{&gc.startIdx, &gc.endIdx, &gc.selected, &gc.pending} {
+		if *p >= n {
+			*p = -1
+		}
+	}
+	for v := range gc.marks {
+		if v >= n {
+			delete(gc.marks, v)
 		}
 	}
+	for e := range gc.blocked {
+		if e[0] >= n || e[1] >= n || !g.HasEdge(e[0], e[1]) {
+			delete(gc.blocked, e)
+		}
+	}
+	gc.colourComponents(g)
+}
+
+// colourComponents recomputes the component colouring from g and redraws.
+func (gc *GraphCanvas) colourComponents(g *Graph) {
+	gc.comp = nil
+	if gc.showComps {
+		gc.comp, _ = g.SCC()
+	}
+	gc.Refresh()
 }
 
 func (gc *GraphCanvas) clearHighlight() {
 	gc.highlightPairs = make(map[[2]int]bool)
+	gc.badges = nil
 	gc.Refresh()
 }
 
 func (gc *GraphCanvas) setHighlightFromPath1(path1 []int) {
 	gc.highlightPairs = make(map[[2]int]bool)
+	gc.badges = nil
 	if len(path1) < 2 {
 		gc.Refresh()
 		return
@@ -561,13 +866,27 @@ func (gc *GraphCanvas) setHighlightFromPath1(path1 []int) {
 
 // ---------------- Editor window ----------------
 
-func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, onChanged func()) {
+type graphEditor struct {
+	w  fyne.Window
+	gc *GraphCanvas
+}
+
+func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, cache *apspCache, onChanged func()) *graphEditor {
 	w := a.NewWindow("Редактор графа (клики)")
 	w.Resize(fyne.NewSize(1000, 640))
 
 	gc := NewGraphCanvas()
+	gc.loadFromGraph(g)
+	gc.onLayout = func() { gc.storeLayout(g) }
 	gc.onChange = func() {
-		gc.syncToGraph(g)
+		if len(gc.verts) == g.n {
+			gc.applyEdgeDiff(cache)
+			gc.storeLayout(g)
+		} else {
+			gc.syncToGraph(g)
+		}
+		gc.badges = nil // tree distances are stale after an edit
+		gc.colourComponents(g)
 		if onChanged != nil {
 			onChanged()
 		}
@@ -582,8 +901,8 @@ func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, onChanged func())
 				done(0, false)
 				return
 			}
-			val, err := strconv.ParseFloat(strings.ReplaceAll(entry.Text, ",", "."), 64)
-			if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+			val, absent, err := infOrFloat(entry.Text)
+			if err != nil || absent {
 				dialog.ShowError(fmt.Errorf("Введите корректное число"), w)
 				done(0, false)
 				return
@@ -609,11 +928,13 @@ func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, onChanged func())
 
 	btnClear := widget.NewButton("Очистить граф", func() {
 		gc.verts = nil
+		gc.labels = nil
 		gc.edges = nil
 		gc.pending = -1
 		gc.dragIdx = -1
 		gc.startIdx = -1
 		gc.endIdx = -1
+		gc.selected = -1
 		gc.clearHighlight()
 		gc.onChange()
 	})
@@ -626,28 +947,128 @@ func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, onChanged func())
 		gc.pick = "end"
 		dialog.ShowInformation("Выбор конечной", "Кликните по вершине на полотне", w)
 	})
+	treeInfo := widget.NewLabel("")
+	treeInfo.Wrapping = fyne.TextWrapWord
+	treeDir := widget.NewSelect([]string{"из начала", "в конец"}, nil)
+	treeDir.SetSelectedIndex(0)
+	buildTree := widget.NewButton("Дерево путей", func() {
+		reverse := treeDir.SelectedIndex() == 1
+		root := gc.startIdx
+		if reverse {
+			root = gc.endIdx
+		}
+		gc.loadFromGraph(g)
+		if root < 0 || root >= g.n {
+			dialog.ShowInformation("Не выбрано", "Сначала выберите начало (для дерева путей в вершину — конец)", w)
+			return
+		}
+		t, err := shortestPathTree(g, root, reverse)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		gc.highlightPairs = make(map[[2]int]bool)
+		for _, e := range t.Edges() {
+			gc.highlightPairs[e] = true
+		}
+		gc.badges = t.Badges()
+		gc.Refresh()
+		if reverse {
+			treeInfo.SetText(fmt.Sprintf("Пути в %s (%s): есть из %d вершин из %d", vertexName(root), t.Algo, t.Reached(), g.n))
+		} else {
+			treeInfo.SetText(fmt.Sprintf("Пути из %s (%s): достижимо %d вершин из %d", vertexName(root), t.Algo, t.Reached(), g.n))
+		}
+	})
 	findPath := widget.NewButton("Найти путь", func() {
 		if gc.startIdx == -1 || gc.endIdx == -1 {
 			dialog.ShowInformation("Не выбрано", "Сначала выберите начало и конец", w)
 			return
 		}
-		gc.syncToGraph(g)
+		gc.loadFromGraph(g)
+		if gc.startIdx == -1 || gc.endIdx == -1 {
+			return
+		}
+		if c := gc.constraints(); !c.empty() {
+			path, d, err := constrainedPath(g, gc.startIdx, gc.endIdx, c)
+			if err != nil {
+				gc.clearHighlight()
+				dialog.ShowError(err, w)
+				return
+			}
+			gc.setHighlightFromPath1(path)
+			msg := fmt.Sprintf("Длина: %g\nПуть: %s\nОграничения: %s", d, joinPathInts(path), c)
+			dialog.ShowInformation("Результат", msg, w)
+			return
+		}
 		if g.HasNegativeEdge() {
 			dialog.ShowError(fmt.Errorf("В графе есть отрицательные дуги — алгоритм Дейкстры неприменим"), w)
 			return
 		}
-		d, prev := g.dijkstraFrom(gc.startIdx)
-		if d[gc.endIdx] >= INF/2 {
+		res, _, err := cache.Get("dijkstra")
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		d, ok := res.Dist(gc.startIdx, gc.endIdx)
+		if !ok {
 			gc.clearHighlight()
 			dialog.ShowInformation("Пути нет", "Между выбранными вершинами пути нет", w)
 			return
 		}
-		path := reconstructFromPrev(prev, gc.startIdx, gc.endIdx)
+		path := res.Path(gc.startIdx, gc.endIdx)
 		gc.setHighlightFromPath1(path)
-		msg := fmt.Sprintf("Длина: %g\nПуть: %s", d[gc.endIdx], joinPathInts(path))
+		msg := fmt.Sprintf("Длина: %g\nПуть: %s", d, joinPathInts(path))
 		dialog.ShowInformation("Результат", msg, w)
 	})
-	clearHL := widget.NewButton("Сброс выделения", func() { gc.clearHighlight() })
+	gc.onMarks = gc.clearHighlight
+	clearMarks := widget.NewButton("Сбросить ограничения", func() { gc.clearConstraints() })
+	marksHint := widget.NewLabel("ПКМ по вершине: через неё → запрещена → без отметки; ПКМ по дуге: запретить")
+	marksHint.Wrapping = fyne.TextWrapWord
+	clearHL := widget.NewButton("Сброс выделения", func() {
+		gc.clearHighlight()
+		treeInfo.SetText("")
+	})
+	compCheck := widget.NewCheck("Цвета компонент сильной связности", func(on bool) {
+		gc.showComps = on
+		gc.colourComponents(g)
+	})
+
+	exportImg := widget.NewButton("Экспорт изображения…", func() {
+		widthEntry := widget.NewEntry()
+		widthEntry.SetText("1000")
+		heightEntry := widget.NewEntry()
+		heightEntry.SetText("750")
+		formatSel := widget.NewSelect([]string{"PNG", "SVG"}, nil)
+		formatSel.SetSelected("PNG")
+		dialog.ShowForm("Экспорт изображения", "Сохранить…", "Отмена", []*widget.FormItem{
+			widget.NewFormItem("Формат", formatSel),
+			widget.NewFormItem("Ширина, px", widthEntry),
+			widget.NewFormItem("Высота, px", heightEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			width, errW := strconv.Atoi(strings.TrimSpace(widthEntry.Text))
+			height, errH := strconv.Atoi(strings.TrimSpace(heightEntry.Text))
+			if errW != nil || errH != nil {
+				dialog.ShowError(fmt.Errorf("Размеры должны быть целыми числами"), w)
+				return
+			}
+			format := strings.ToLower(formatSel.Selected)
+			sc := gc.scene()
+			d := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil || uc == nil {
+					return
+				}
+				defer uc.Close()
+				if e := writeSceneImage(uc, sc, format, width, height); e != nil {
+					dialog.ShowError(e, w)
+				}
+			}, w)
+			d.SetFileName("graph." + format)
+			d.Show()
+		}, w)
+	})
 
 	left := container.NewVBox(
 		modes,
@@ -655,27 +1076,41 @@ func openGraphEditor(a fyne.App, parent fyne.Window, g *Graph, onChanged func())
 		btnClear,
 		widget.NewSeparator(),
 		widget.NewLabelWithStyle("Выделение пути (как в ЛР1):", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-		pickStart, pickEnd, findPath, clearHL,
+		container.NewHBox(pickStart, pickEnd, findPath, clearHL),
+		container.NewHBox(clearMarks, buildTree, treeDir),
+		marksHint, treeInfo,
+		widget.NewSeparator(),
+		compCheck, exportImg,
 	)
 
 	w.SetContent(container.NewBorder(left, nil, nil, nil, container.NewMax(gc)))
 	w.Show()
+	return &graphEditor{w: w, gc: gc}
 }
 
 // ---------------- Main window (matrix + all-pairs results) ----------------
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	a := app.New()
 	w := a.NewWindow("ЛР №2 — Все пары кратчайших путей (Go/Fyne)")
 	w.Resize(fyne.NewSize(1040, 680))
 
 	g := NewGraph()
 	g.Resize(4)
+	cache := newAPSPCache(g)
 
 	status := binding.NewString()
 	status.Set("Готово")
+	edgeInfo := binding.NewString()
+	// cellIssues holds matrix cells whose text could not be applied to g
+	cellIssues := make(map[[2]int]string)
 
 	var buildMatrixGrid func()
+	var refreshLive func()
 	matrixGrid := container.NewVBox()
 
 	nEntry := widget.NewEntry()
@@ -689,10 +1124,12 @@ func main() {
 		g.Resize(nVal)
 		status.Set(fmt.Sprintf("Размер матрицы: %d", g.n))
 		buildMatrixGrid()
+		refreshLive()
 	})
 
 	buildMatrixGrid = func() {
 		matrixGrid.Objects = nil
+		clear(cellIssues)
 		if g.n == 0 {
 			matrixGrid.Add(widget.NewLabel("Матрица пуста — установите N > 0"))
 			matrixGrid.Refresh()
@@ -710,16 +1147,33 @@ func main() {
 			for j := 0; j < g.n; j++ {
 				cell := widget.NewEntry()
 				cell.SetPlaceHolder("∞ = пусто")
-				cell.SetText(floatToCell(g.adj[i][j], i, j))
+				cell.SetText(floatToCell(g, i, j))
+				// the outline is drawn over the entry, whose background is opaque
+				mark := canvas.NewRectangle(color.Transparent)
+				mark.StrokeColor, mark.StrokeWidth = invalidCellColor, 2
+				mark.Hide()
 				ci, cj := i, j
 				cell.OnChanged = func(s string) {
-					v, isInf, err := infOrFloat(s)
-					if err != nil {
-						return
+					key := [2]int{ci, cj}
+					v, absent, err := infOrFloat(s)
+					switch {
+					case err != nil:
+						cellIssues[key] = fmt.Sprintf("%v «%s»", err, strings.TrimSpace(s))
+					case ci == cj && !absent && v != 0:
+						cellIssues[key] = "на диагонали допускается только 0"
+					default:
+						delete(cellIssues, key)
+						cache.SetEdge(ci, cj, v, absent)
+						g.SetExactText(ci, cj, s)
+					}
+					if _, bad := cellIssues[key]; bad {
+						mark.Show()
+					} else {
+						mark.Hide()
 					}
-					g.SetEdge(ci, cj, v, isInf)
+					refreshLive()
 				}
-				row.Add(cell)
+				row.Add(container.NewStack(cell, mark))
 			}
 			matrixGrid.Add(row)
 		}
@@ -727,168 +1181,309 @@ func main() {
 	}
 	buildMatrixGrid()
 
-	// Results table (fast)
-	type resRow struct{ I, J, Length, Path string }
-	resData := make([]resRow, 0)
-	resultsTable := widget.NewTable(
-		func() (int, int) { return len(resData), 4 },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(id widget.TableCellID, co fyne.CanvasObject) {
-			if id.Row >= len(resData) {
-				co.(*widget.Label).SetText("")
-				return
+	// Results list (sortable, filterable)
+	resultsList := newResultsList()
+	resultsTable := container.NewBorder(resultsList.bar, nil, nil, nil, resultsList.table)
+
+	var editor *graphEditor
+
+	diag := newDiagnosticsPanel()
+	diagTab := container.NewTabItem("Диагностика", diag.box)
+	var resultTabs *container.AppTabs
+	refreshDiagnostics := func() {
+		errs, warns := diag.Update(g, cellIssues)
+		diagTab.Text = "Диагностика"
+		if errs+warns > 0 {
+			diagTab.Text = fmt.Sprintf("Диагностика (%d)", errs+warns)
+		}
+		if resultTabs != nil {
+			resultTabs.Refresh()
+		}
+	}
+	diag.onSource = refreshDiagnostics
+
+	// the structure and metrics tabs are recomputed only while visible
+	comps := newComponentsPanel()
+	compTab := container.NewTabItem("Связность", comps.box)
+	metrics := newMetricsPanel()
+	metricsTab := container.NewTabItem("Метрики", metrics.box)
+	cent := newCentralityPanel()
+	centTab := container.NewTabItem("Центральность", cent.box)
+	hops := newHopPanel()
+	hopTab := container.NewTabItem("Ограничение рёбер", hops.box)
+	refreshStructure := func() {
+		if resultTabs == nil {
+			return
+		}
+		if resultTabs.Selected() == compTab {
+			comps.Update(g)
+		}
+		// the canvas marks follow the metrics even while the tab is hidden
+		if resultTabs.Selected() == metricsTab || metrics.mark.Checked {
+			res, _, err := cache.Get("floyd")
+			metrics.Update(res, err)
+		}
+		if resultTabs.Selected() == centTab || cent.scale.Checked {
+			cent.Update(g)
+		}
+		if resultTabs.Selected() == hopTab {
+			res, _, err := cache.Get("floyd")
+			hops.Update(g, res, err)
+		}
+		if editor != nil {
+			editor.gc.roles = metrics.Roles()
+			editor.gc.scale = cent.Scale()
+			editor.gc.Refresh()
+		}
+	}
+	metrics.onMark = refreshStructure
+	cent.onScale = refreshStructure
+	hops.onChange = refreshStructure
+
+	openEditor := func() {
+		if editor != nil {
+			editor.w.RequestFocus()
+			return
+		}
+		editor = openGraphEditor(a, w, g, cache, func() {
+			buildMatrixGrid()
+			refreshLive()
+		})
+		refreshStructure()
+		editor.gc.onSelect = func(v int) {
+			resultsList.SetSource(v)
+			if v >= 0 {
+				diag.SetSource(v)
+				refreshDiagnostics()
 			}
-			r := resData[id.Row]
-			var txt string
-			switch id.Col {
-			case 0:
-				txt = r.I
-			case 1:
-				txt = r.J
-			case 2:
-				txt = r.Length
-			case 3:
-				txt = r.Path
+		}
+		editor.w.SetOnClosed(func() { editor = nil })
+	}
+	resultsList.onPick = func(r resRow) {
+		openEditor()
+		editor.gc.loadFromGraph(g)
+		if !r.reachable() {
+			editor.gc.clearHighlight()
+			status.Set(fmt.Sprintf("Из %s в %s пути нет", vertexName(r.I), vertexName(r.J)))
+			return
+		}
+		editor.gc.setHighlightFromPath1(r.Path)
+		status.Set(fmt.Sprintf("%s → %s: %s", vertexName(r.I), vertexName(r.J), r.pathText()))
+	}
+	var shown *APSPResult
+	matrixView := newDistMatrixView(func(i, j int) {
+		if i == j || shown == nil {
+			return
+		}
+		if !shown.Reachable(i, j) {
+			if editor != nil {
+				editor.gc.clearHighlight()
 			}
-			co.(*widget.Label).SetText(txt)
-		},
-	)
-	resultsTable.SetColumnWidth(0, 36)
-	resultsTable.SetColumnWidth(1, 36)
-	resultsTable.SetColumnWidth(2, 92)
-	resultsTable.SetColumnWidth(3, 420)
-
-	updateResults := func(dist [][]float64, getPath func(i, j int) []int) {
-		n := len(dist)
-		res := make([]resRow, 0, n*(n-1))
+			dialog.ShowInformation("Пути нет", fmt.Sprintf("Из %s в %s пути нет", vertexName(i), vertexName(j)), w)
+			return
+		}
+		path := shown.Path(i, j)
+		if editor != nil {
+			editor.gc.loadFromGraph(g)
+			editor.gc.setHighlightFromPath1(path)
+		}
+		msg := fmt.Sprintf("%s: %s\nПуть: %s", semiringOf(shown).Value, shown.DistText(i, j), joinPathInts(path))
+		dialog.ShowInformation(fmt.Sprintf("%s → %s", vertexName(i), vertexName(j)), msg, w)
+	})
+	matrixView.table.Hide()
+
+	viewSwitch := widget.NewRadioGroup([]string{"Список пар", "Матрица расстояний"}, func(s string) {
+		if s == "Матрица расстояний" {
+			resultsTable.Hide()
+			matrixView.table.Show()
+		} else {
+			matrixView.table.Hide()
+			resultsTable.Show()
+		}
+	})
+	viewSwitch.Horizontal = true
+	viewSwitch.Required = true
+	viewSwitch.SetSelected("Список пар")
+
+	updateResults := func(res *APSPResult) {
+		n := res.N()
+		rows := make([]resRow, 0, n*(n-1))
 		for i := 0; i < n; i++ {
 			for j := 0; j < n; j++ {
 				if i == j {
 					continue
 				}
-				length := "∞"
-				if dist[i][j] < INF/2 {
-					length = strconv.FormatFloat(dist[i][j], 'g', -1, 64)
-				}
-				var pathStr string
-				if dist[i][j] >= INF/2 {
-					pathStr = "пути нет"
-				} else {
-					p := getPath(i, j)
-					if len(p) == 0 {
-						pathStr = "-"
-					} else {
-						var b strings.Builder
-						for k, v := range p {
-							if k > 0 {
-								b.WriteString(" → ")
-							}
-							b.WriteString(strconv.Itoa(v))
-						}
-						pathStr = b.String()
-					}
+				d, ok := res.Dist(i, j)
+				row := resRow{I: i, J: j, Reach: ok, Length: d, Path: res.Path(i, j)}
+				if res.Weights != "" && ok {
+					row.Text = res.DistText(i, j)
 				}
-				res = append(res, resRow{I: strconv.Itoa(i + 1), J: strconv.Itoa(j + 1), Length: length, Path: pathStr})
+				rows = append(rows, row)
 			}
 		}
-		resData = res
-		resultsTable.Refresh()
-		status.Set(fmt.Sprintf("Готово: %d записей", len(resData)))
+		resultsList.SetValueTitle(semiringOf(res).Value)
+		resultsList.SetRows(rows, n)
+		shown = res
+		matrixView.SetDist(res.N(), res.Dist, res.DistText)
+		status.Set(fmt.Sprintf("Готово: %d записей", len(rows)))
+	}
+
+	weights := "float"
+	var weightTitles []string
+	for _, k := range weightKinds {
+		weightTitles = append(weightTitles, k.Title)
+	}
+	weightSel := widget.NewSelect(weightTitles, func(string) {})
+	weightSel.SetSelectedIndex(0)
+	weightSel.OnChanged = func(string) { weights = weightKinds[weightSel.SelectedIndex()].Key }
+
+	paths := "shortest"
+	var pathTitles []string
+	for _, s := range pathSemirings {
+		pathTitles = append(pathTitles, s.Title)
 	}
+	pathSel := widget.NewSelect(pathTitles, func(string) {})
+	pathSel.SetSelectedIndex(0)
+	pathSel.OnChanged = func(string) { paths = pathSemirings[pathSel.SelectedIndex()].Key }
 
-	btnFloyd := widget.NewButton("Все пары (Флойд)", func() {
+	runSolver := func(key string) {
 		if g.n == 0 {
 			dialog.ShowInformation("Пусто", "Сначала установите N > 0", w)
 			return
 		}
-		dist, pred, neg := g.FloydWarshall()
-		if neg {
-			dialog.ShowError(fmt.Errorf("Обнаружен отрицательный цикл — решения нет"), w)
+		sv, _ := solverByKey(key)
+		title := sv.Title
+		if weights != "float" {
+			title += ", " + weightTitle(weights)
+		}
+		if paths != "shortest" {
+			title += ", " + semiringTitle(paths)
+		}
+		res, cached, err := cache.Get(solverCacheKey(key, weights, paths))
+		if err != nil {
+			dialog.ShowError(err, w)
 			return
 		}
-		updateResults(dist, func(i, j int) []int { return reconstructPathPred(pred, i, j) })
-		status.Set("Флойд: готово")
-	})
-
-	btnDij := widget.NewButton("Все пары (n×Дейкстра)", func() {
-		if g.n == 0 {
-			dialog.ShowInformation("Пусто", "Сначала установите N > 0", w)
+		updateResults(res)
+		if cached {
+			status.Set(fmt.Sprintf("%s: граф не менялся, результат из кэша", title))
 			return
 		}
-		if g.HasNegativeEdge() {
-			dialog.ShowError(fmt.Errorf("Есть отрицательные дуги — n×Дейкстра неприменим"), w)
+		status.Set(fmt.Sprintf("%s: готово за %s — %s", title, res.Elapsed, res.Stats.String()))
+	}
+	// refreshLive keeps the shown results in step with edits, reusing the
+	// incrementally updated cache entry when there is one.
+	refreshLive = func() {
+		edgeInfo.Set(g.EdgeStats().String())
+		refreshDiagnostics()
+		refreshStructure()
+		if shown == nil {
 			return
 		}
-		n := g.n
-		dist := make([][]float64, n)
-		prevAll := make([][]int, n)
-		for s := 0; s < n; s++ {
-			d, p := g.dijkstraFrom(s)
-			dist[s] = d
-			prevAll[s] = p
+		sv, _ := solverByKey(shown.Algo)
+		res, cached, err := cache.Get(shown.Key())
+		if err != nil {
+			status.Set(fmt.Sprintf("%s: результаты устарели — %v", sv.Title, err))
+			return
 		}
-		updateResults(dist, func(i, j int) []int { return reconstructFromPrev(prevAll[i], i, j) })
-		status.Set("n×Дейкстра: готово")
+		updateResults(res)
+		if cached && res.Updates > 0 {
+			status.Set(fmt.Sprintf("%s: обновлено инкрементально (правок: %d) — %s", sv.Title, res.Updates, res.Update.String()))
+		} else if !cached {
+			status.Set(fmt.Sprintf("%s: пересчитано за %s — %s", sv.Title, res.Elapsed, res.Stats.String()))
+		}
+	}
+
+	btnFloyd := widget.NewButton("Все пары (Флойд)", func() { runSolver("floyd") })
+	btnDij := widget.NewButton("Все пары (n×Дейкстра)", func() { runSolver("dijkstra") })
+	btnCompare := widget.NewButton("Сравнить алгоритмы…", func() {
+		if g.n == 0 {
+			dialog.ShowInformation("Пусто", "Сначала установите N > 0", w)
+			return
+		}
+		showSolverComparison(a, g)
 	})
 
-	btnExport := widget.NewButton("Экспорт CSV", func() {
+	btnExport := widget.NewButton("Экспорт результатов…", func() {
 		if g.n == 0 {
 			dialog.ShowInformation("Пусто", "Нет данных для экспорта", w)
 			return
 		}
-		dist, pred, neg := g.FloydWarshall()
-		if neg {
-			dialog.ShowError(fmt.Errorf("Отрицательный цикл — CSV невозможен"), w)
-			return
+		showExportDialog(w, g, cache)
+	})
+
+	replaceGraph := func(ng *Graph) {
+		g.assign(ng)
+		nEntry.SetText(strconv.Itoa(g.n))
+		if editor != nil {
+			editor.gc.verts = nil
+			editor.gc.loadFromGraph(g)
 		}
-		getPath := func(i, j int) []int { return reconstructPathPred(pred, i, j) }
-		dialog.ShowFileSave(func(uc fyne.URIWriteCloser, err error) {
+		buildMatrixGrid()
+		refreshLive()
+	}
+
+	btnImport := widget.NewButton("Импорт графа…", func() {
+		dialog.ShowFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil || rc == nil {
+				return
+			}
+			defer rc.Close()
+			f, ok := graphFormatFor(rc.URI().Name())
+			if !ok {
+				dialog.ShowError(fmt.Errorf("Неизвестный формат файла — ожидается .dot, .graphml или .json"), w)
+				return
+			}
+			ng, err := f.Read(rc)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if ng.n > MaxVertices {
+				dialog.ShowError(fmt.Errorf("В графе %d вершин, интерфейс поддерживает до %d — используйте apsp solve --format %s", ng.n, MaxVertices, f.Key), w)
+				return
+			}
+			ng.fitLayout()
+			replaceGraph(ng)
+			status.Set(fmt.Sprintf("Импортирован граф (%s): %d вершин", f.Title, g.n))
+		}, w)
+	})
+	btnSaveGraph := widget.NewButton("Экспорт графа…", func() {
+		d := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
 			if err != nil || uc == nil {
 				return
 			}
 			defer uc.Close()
-			wrt := csv.NewWriter(uc)
-			wrt.Comma = ';'
-			wrt.Write([]string{"i", "j", "length", "path"})
-			n := len(dist)
-			for i := 0; i < n; i++ {
-				for j := 0; j < n; j++ {
-					if i == j {
-						continue
-					}
-					length := "inf"
-					if dist[i][j] < INF/2 {
-						length = strconv.FormatFloat(dist[i][j], 'g', -1, 64)
-					}
-					p := getPath(i, j)
-					pathStr := ""
-					if len(p) == 0 && dist[i][j] < INF/2 {
-						pathStr = "-"
-					} else if len(p) > 0 {
-						parts := make([]string, len(p))
-						for k, v := range p {
-							parts[k] = strconv.Itoa(v)
-						}
-						pathStr = strings.Join(parts, " ")
-					}
-					wrt.Write([]string{strconv.Itoa(i + 1), strconv.Itoa(j + 1), length, pathStr})
-				}
+			f, ok := graphFormatFor(uc.URI().Name())
+			if !ok {
+				dialog.ShowError(fmt.Errorf("Укажите расширение .dot, .graphml или .json"), w)
+				return
 			}
-			wrt.Flush()
-			if e := wrt.Error(); e != nil {
+			if e := f.Write(uc, g); e != nil {
 				dialog.ShowError(e, w)
 			} else {
-				dialog.ShowInformation("Готово", "CSV сохранён", w)
+				dialog.ShowInformation("Готово", "Граф сохранён ("+f.Title+")", w)
 			}
 		}, w)
+		d.SetFileName("graph.graphml")
+		d.Show()
+	})
+
+	btnRandom := widget.NewButton("Случайный граф…", func() {
+		showGeneratorDialog(w, func(ng *Graph, desc string) {
+			replaceGraph(ng)
+			status.Set(desc)
+		})
 	})
 
-	btnEditor := widget.NewButton("Редактор графа (клики)…", func() { openGraphEditor(a, w, g, func() { buildMatrixGrid() }) })
+	btnEditor := widget.NewButton("Редактор графа (клики)…", openEditor)
 
 	controls := container.NewVBox(
 		container.NewHBox(widget.NewLabel("Число вершин N:"), nEntry, setNBtn, btnEditor),
+		container.NewHBox(btnImport, btnSaveGraph, btnRandom),
 		widget.NewSeparator(),
-		container.NewHBox(btnFloyd, btnDij, btnExport),
+		container.NewHBox(btnFloyd, btnDij, btnCompare, btnExport),
+		container.NewHBox(widget.NewLabel("Арифметика:"), weightSel, widget.NewLabel("Пути:"), pathSel),
 		widget.NewSeparator(),
 		widget.NewLabelWithStyle("Матрица весов (∞ — пусто, диагональ 0)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 	)
@@ -896,8 +1491,14 @@ func main() {
 	split := container.NewVSplit(controls, container.NewVScroll(matrixGrid))
 	split.Offset = 0.25
 
-	statusBar := widget.NewLabelWithData(status)
-	content := container.NewBorder(nil, statusBar, nil, nil, container.NewHSplit(split, resultsTable))
+	results := container.NewBorder(viewSwitch, nil, nil, nil, container.NewStack(resultsTable, matrixView.table))
+	resultTabs = container.NewAppTabs(container.NewTabItem("Результаты", results), diagTab, compTab, metricsTab, centTab, hopTab)
+	resultTabs.OnSelected = func(*container.TabItem) { refreshStructure() }
+	refreshDiagnostics()
+
+	edgeInfo.Set(g.EdgeStats().String())
+	statusBar := container.NewBorder(nil, nil, nil, widget.NewLabelWithData(edgeInfo), widget.NewLabelWithData(status))
+	content := container.NewBorder(nil, statusBar, nil, nil, container.NewHSplit(split, resultTabs))
 	w.SetContent(content)
 	w.ShowAndRun()
 }
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ---------------- Dense matrix ----------------
+
+// Matrix is a dense n×n matrix stored row-major in a single slice, so a row
+// is contiguous in memory and the whole matrix is one allocation.
+type Matrix[T any] struct {
+	n    int
+	data []T
+}
+
+// NewMatrix returns an n×n matrix with every cell set to fill.
+func NewMatrix[T any](n int, fill T) Matrix[T] {
+	m := Matrix[T]{n: n, data: make([]T, n*n)}
+	for k := range m.data {
+		m.data[k] = fill
+	}
+	return m
+}
+
+func (m *Matrix[T]) N() int { return m.n }
+
+func (m *Matrix[T]) index(i, j int) int {
+	if uint(i) >= uint(m.n) || uint(j) >= uint(m.n) {
+		panic(fmt.Sprintf("matrix: index (%d, %d) out of range for %d×%d", i, j, m.n, m.n))
+	}
+	return i*m.n + j
+}
+
+func (m *Matrix[T]) At(i, j int) T { return m.data[m.index(i, j)] }
+
+func (m *Matrix[T]) Set(i, j int, v T) { m.data[m.index(i, j)] = v }
+
+// Row returns row i as a slice sharing the matrix storage.
+func (m *Matrix[T]) Row(i int) []T {
+	if uint(i) >= uint(m.n) {
+		panic(fmt.Sprintf("matrix: row %d out of range for %d×%d", i, m.n, m.n))
+	}
+	return m.data[i*m.n : (i+1)*m.n : (i+1)*m.n]
+}
+
+func (m *Matrix[T]) Clone() Matrix[T] {
+	return Matrix[T]{n: m.n, data: append([]T(nil), m.data...)}
+}
+
+// Resized returns an n×n copy keeping the overlapping top-left block and
+// filling new cells with fill.
+func (m *Matrix[T]) Resized(n int, fill T) Matrix[T] {
+	out := NewMatrix(n, fill)
+	for i := 0; i < min(n, m.n); i++ {
+		copy(out.Row(i), m.Row(i)[:min(n, m.n)])
+	}
+	return out
+}
+
+// ---------------- Floyd–Warshall kernels ----------------
+
+// floydBlock is the tile size of the blocked kernel: three 64×64 tiles of
+// float64 fit in a typical 32 KiB L1 cache together with the int tiles.
+const floydBlock = 64
+
+// floydNoTiles makes floydBlocked run as a single tile, i.e. the plain triple loop.
+const floydNoTiles = math.MaxInt32
+
+// floydTilesFrom is the smallest n that gets tiles. Below it dist and pred
+// still mostly fit in cache and the plain loop is as fast or faster
+// (BenchmarkFloydFlat vs BenchmarkFloydBlocked: tiles lose ~5% at n = 600,
+// tie at 1000 and win ~14% at 2000).
+const floydTilesFrom = 1500
+
+// floydBlockFor picks the tile size for an n×n matrix.
+func floydBlockFor(n int) int {
+	if n < floydTilesFrom {
+		return floydNoTiles
+	}
+	return floydBlock
+}
+
+// floydInit copies the adjacency matrix into a distance matrix and sets the
+// initial predecessors (i for every existing edge i → j and for j == i, -1
+// otherwise). Reachability is carried by pred alone, so distances need no
+// sentinel value.
+func floydInit(g *Graph) (Matrix[float64], Matrix[int]) {
+	dist := g.adj.Clone()
+	pred := NewMatrix(g.n, -1)
+	for k, ok := range g.has.data {
+		if ok {
+			pred.data[k] = k / g.n
+		}
+	}
+	for i := 0; i < g.n; i++ {
+		pred.Set(i, i, i)
+	}
+	return dist, pred
+}
+
+// floydBlocked runs Floyd–Warshall over b×b tiles. For every diagonal tile
+// kb it first closes the tile itself, then the tiles of row kb and column kb,
+// then all the rest, so each tile only reads tiles already final for this k
+// range. The relaxations are the same as in the plain triple loop, just in a
+// cache-friendly order.
+func floydBlocked(dist Matrix[float64], pred Matrix[int], b int, st *SolverStats) {
+	var c floydCounts
+	floydTiles(dist.n, b, func(i0, i1, j0, j1, k0, k1 int) {
+		c.tile(dist, pred, i0, i1, j0, j1, k0, k1)
+	})
+	c.addTo(st)
+}
+
+// floydBlockedWith is floydBlocked over an arbitrary path semiring.
+func floydBlockedWith(dist Matrix[float64], pred Matrix[int], b int, sr pathSemiring, st *SolverStats) {
+	var c floydCounts
+	floydTiles(dist.n, b, func(i0, i1, j0, j1, k0, k1 int) {
+		c.tileWith(sr, dist, pred, i0, i1, j0, j1, k0, k1)
+	})
+	c.addTo(st)
+}
+
+// floydTiles calls tile for every (i range, j range, k range) in the order
+// the blocked kernel needs.
+func floydTiles(n, b int, tile func(i0, i1, j0, j1, k0, k1 int)) {
+	for kb := 0; kb < n; kb += b {
+		ke := min(kb+b, n)
+		tile(kb, ke, kb, ke, kb, ke)
+		for jb := 0; jb < n; jb += b {
+			if jb != kb {
+				tile(kb, ke, jb, min(jb+b, n), kb, ke)
+			}
+		}
+		for ib := 0; ib < n; ib += b {
+			if ib != kb {
+				tile(ib, min(ib+b, n), kb, ke, kb, ke)
+			}
+		}
+		for ib := 0; ib < n; ib += b {
+			if ib == kb {
+				continue
+			}
+			for jb := 0; jb < n; jb += b {
+				if jb != kb {
+					tile(ib, min(ib+b, n), jb, min(jb+b, n), kb, ke)
+				}
+			}
+		}
+	}
+}
+
+// floydCounts accumulates operation counts in locals instead of going through
+// the nil-checked SolverStats methods in the innermost loop.
+type floydCounts struct{ iter, relax, improved, cmp int64 }
+
+func (c *floydCounts) tile(dist Matrix[float64], pred Matrix[int], i0, i1, j0, j1, k0, k1 int) {
+	n := dist.n
+	d, p := dist.data, pred.data
+	var iter, relax, improved int64
+	for k := k0; k < k1; k++ {
+		dk := d[k*n+j0 : k*n+j1]
+		pk := p[k*n+j0 : k*n+j1]
+		for i := i0; i < i1; i++ {
+			if p[i*n+k] < 0 {
+				continue
+			}
+			dik := d[i*n+k]
+			di := d[i*n+j0 : i*n+j1]
+			pi := p[i*n+j0 : i*n+j1]
+			di, pi = di[:len(dk)], pi[:len(dk)]
+			iter += int64(len(dk))
+			for j, dkj := range dk {
+				if pk[j] < 0 {
+					continue
+				}
+				relax++
+				if cand := dik + dkj; pi[j] < 0 || cand < di[j] {
+					improved++
+					di[j] = cand
+					pi[j] = pk[j]
+				}
+			}
+		}
+	}
+	c.cmp += int64((k1 - k0) * (i1 - i0))
+	c.iter += iter
+	c.relax += relax
+	c.improved += improved
+}
+
+func (c *floydCounts) tileWith(sr pathSemiring, dist Matrix[float64], pred Matrix[int], i0, i1, j0, j1, k0, k1 int) {
+	n := dist.n
+	d, p := dist.data, pred.data
+	var iter, relax, improved int64
+	for k := k0; k < k1; k++ {
+		dk := d[k*n+j0 : k*n+j1]
+		pk := p[k*n+j0 : k*n+j1]
+		for i := i0; i < i1; i++ {
+			if p[i*n+k] < 0 {
+				continue
+			}
+			dik := d[i*n+k]
+			di := d[i*n+j0 : i*n+j1]
+			pi := p[i*n+j0 : i*n+j1]
+			di, pi = di[:len(dk)], pi[:len(dk)]
+			iter += int64(len(dk))
+			for j, dkj := range dk {
+				if pk[j] < 0 {
+					continue
+				}
+				relax++
+				if cand := sr.Extend(dik, dkj); pi[j] < 0 || sr.Better(cand, di[j]) {
+					improved++
+					di[j] = cand
+					pi[j] = pk[j]
+				}
+			}
+		}
+	}
+	c.cmp += int64((k1 - k0) * (i1 - i0))
+	c.iter += iter
+	c.relax += relax
+	c.improved += improved
+}
+
+func (c *floydCounts) addTo(st *SolverStats) {
+	if st == nil {
+		return
+	}
+	st.Iterations += c.iter
+	st.Relaxations += c.relax
+	st.Improved += c.improved
+	// every iteration checks whether j is reachable from k, every relaxation compares
+	st.Comparisons += c.cmp + c.iter + c.relax
+}
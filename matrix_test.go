@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func benchGraph(b *testing.B, n int) *Graph {
+	g, err := generateGraph("gnp", genParams{N: n, P: 0.1, Seed: 1, MinW: 1, MaxW: 100, Integer: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return g
+}
+
+func benchFloyd(b *testing.B, block int) {
+	for _, n := range []int{500, 1000, 2000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			g := benchGraph(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dist, pred := floydInit(g)
+				floydBlocked(dist, pred, block, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkFloydFlat(b *testing.B) { benchFloyd(b, floydNoTiles) }
+
+func BenchmarkFloydBlocked(b *testing.B) { benchFloyd(b, floydBlock) }
+
+// TestFloydBlockedMatchesFlat compares the tiled kernel with the plain triple
+// loop. Tiles reach the same shortest paths in another order, so weights are
+// integers (float sums could differ in the last bit) and a predecessor may be
+// another vertex of an equally short path: it must then be the last edge of a
+// shortest path. Negative weights only go into DAGs, which have no cycles.
+func TestFloydBlockedMatchesFlat(t *testing.T) {
+	for seed := uint64(1); seed <= 30; seed++ {
+		n := int(seed*7) % 150
+		p := genParams{N: n, P: 0.15, Seed: seed, MinW: 1, MaxW: 40, Integer: true}
+		kind := "gnp"
+		if seed%2 == 0 {
+			kind, p.MinW, p.NegProb = "dag", -5, 0.3
+		}
+		g, err := generateGraph(kind, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, wantPred := floydInit(g)
+		floydBlocked(want, wantPred, floydNoTiles, nil)
+		for _, block := range []int{1, 7, 16, floydBlock} {
+			dist, pred := floydInit(g)
+			floydBlocked(dist, pred, block, nil)
+			for k := range want.data {
+				i, j, p := k/n, k%n, pred.data[k]
+				ok := (p < 0) == (wantPred.data[k] < 0) && (p < 0 || dist.data[k] == want.data[k])
+				if ok && p >= 0 && p != wantPred.data[k] {
+					w, has := g.Edge(p, j)
+					ok = has && dist.At(i, p)+w == dist.data[k]
+				}
+				if !ok {
+					t.Fatalf("%s seed %d, n %d, block %d: (%d, %d) = %v/%d, flat %v/%d",
+						kind, seed, n, block, i+1, j+1, dist.data[k], p, want.data[k], wantPred.data[k])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Distance matrix view ----------------
+
+// distMatrixView shows all-pairs distances as an n×n table coloured by distance.
+type distMatrixView struct {
+	table  *widget.Table
+	size   int
+	dist   func(i, j int) (float64, bool)
+	text   func(i, j int) string
+	lo, hi float64
+
+	onPick func(i, j int)
+}
+
+func newDistMatrixView(onPick func(i, j int)) *distMatrixView {
+	v := &distMatrixView{onPick: onPick}
+	v.table = widget.NewTable(
+		func() (int, int) { return v.n(), v.n() },
+		func() fyne.CanvasObject {
+			bg := canvas.NewRectangle(color.Transparent)
+			return container.NewStack(bg, widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{}))
+		},
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			c := co.(*fyne.Container)
+			bg := c.Objects[0].(*canvas.Rectangle)
+			lbl := c.Objects[1].(*widget.Label)
+			if id.Row >= v.n() || id.Col >= v.n() {
+				bg.FillColor = color.Transparent
+				lbl.SetText("")
+				bg.Refresh()
+				return
+			}
+			d, ok := v.dist(id.Row, id.Col)
+			switch {
+			case id.Row == id.Col:
+				bg.FillColor = color.NRGBA{R: 238, G: 238, B: 238, A: 255}
+			case !ok:
+				bg.FillColor = color.NRGBA{R: 210, G: 210, B: 210, A: 255}
+			default:
+				bg.FillColor = v.heat(d)
+			}
+			lbl.SetText(v.text(id.Row, id.Col))
+			bg.Refresh()
+		},
+	)
+	v.table.ShowHeaderRow = true
+	v.table.ShowHeaderColumn = true
+	v.table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	}
+	v.table.UpdateHeader = func(id widget.TableCellID, co fyne.CanvasObject) {
+		lbl := co.(*widget.Label)
+		switch {
+		case id.Row < 0 && id.Col < 0:
+			lbl.SetText("i/j")
+		case id.Row < 0:
+			lbl.SetText(vertexName(id.Col))
+		default:
+			lbl.SetText(vertexName(id.Row))
+		}
+	}
+	v.table.OnSelected = func(id widget.TableCellID) {
+		v.table.Unselect(id)
+		if id.Row < 0 || id.Col < 0 || id.Row >= v.n() || id.Col >= v.n() {
+			return
+		}
+		if v.onPick != nil {
+			v.onPick(id.Row, id.Col)
+		}
+	}
+	return v
+}
+
+// SetDist replaces the shown n×n matrix and recomputes the heat-map range;
+// dist reports unreachable cells, text formats a cell (exact results show
+// their own digits).
+func (v *distMatrixView) SetDist(n int, dist func(i, j int) (float64, bool), text func(i, j int) string) {
+	v.size, v.dist, v.text = n, dist, text
+	v.lo, v.hi = math.Inf(1), math.Inf(-1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			d, ok := dist(i, j)
+			if i == j || !ok {
+				continue
+			}
+			v.lo = math.Min(v.lo, d)
+			v.hi = math.Max(v.hi, d)
+		}
+	}
+	for j := 0; j < v.n(); j++ {
+		v.table.SetColumnWidth(j, 64)
+	}
+	v.table.Refresh()
+}
+
+func (v *distMatrixView) n() int { return v.size }
+
+// heat maps a finite distance onto a green → yellow → red scale.
+func (v *distMatrixView) heat(d float64) color.Color {
+	low := color.NRGBA{R: 198, G: 239, B: 206, A: 255}
+	mid := color.NRGBA{R: 255, G: 235, B: 156, A: 255}
+	high := color.NRGBA{R: 255, G: 199, B: 206, A: 255}
+	t := 0.0
+	if v.hi > v.lo {
+		t = (d - v.lo) / (v.hi - v.lo)
+	}
+	if t < 0.5 {
+		return lerpColor(low, mid, t*2)
+	}
+	return lerpColor(mid, high, (t-0.5)*2)
+}
+
+func lerpColor(a, b color.NRGBA, t float64) color.NRGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*t) }
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
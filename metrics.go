@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Distance-based graph metrics ----------------
+
+// vertexRole marks center and periphery vertices; a vertex can be both when
+// all eccentricities are equal.
+type vertexRole uint8
+
+const (
+	roleCenter vertexRole = 1 << iota
+	rolePeriphery
+)
+
+// graphMetrics are derived from an all-pairs result. Eccentricities follow
+// the strict definition: a vertex that does not reach every other one has
+// infinite eccentricity, so radius and diameter are infinite for graphs that
+// are not strongly connected. Longest is the largest finite distance, which
+// equals the diameter when the diameter is finite.
+type graphMetrics struct {
+	Ecc              []float64 // +Inf when some vertex is unreachable
+	Radius, Diameter float64
+	Roles            []vertexRole
+
+	Longest     float64
+	LongestPath []int // 1-based, nil when no pair i ≠ j is reachable
+
+	AvgLength float64 // over reachable ordered pairs i ≠ j
+	Pairs     int
+
+	// Closeness is Wasserman–Faust closeness: (r/(n-1))·(r/Σd) over the r
+	// vertices reachable from v; NaN when Σd ≤ 0 (negative weights).
+	Closeness []float64
+}
+
+func computeMetrics(res *APSPResult) graphMetrics {
+	n := res.N()
+	m := graphMetrics{
+		Ecc: make([]float64, n), Roles: make([]vertexRole, n), Closeness: make([]float64, n),
+		Radius: math.Inf(1), Diameter: math.Inf(-1), Longest: math.Inf(-1),
+	}
+	var total float64
+	for i := 0; i < n; i++ {
+		// -Inf, not 0: with negative weights all distances from i may be < 0
+		m.Ecc[i] = math.Inf(-1)
+		var sum float64
+		reached := 0
+		for j := 0; j < n; j++ {
+			d, ok := res.Dist(i, j)
+			if i == j {
+				continue
+			}
+			if !ok {
+				m.Ecc[i] = math.Inf(1)
+				continue
+			}
+			m.Ecc[i] = math.Max(m.Ecc[i], d)
+			sum += d
+			reached++
+			if d > m.Longest {
+				m.Longest, m.LongestPath = d, res.Path(i, j)
+			}
+		}
+		if n == 1 {
+			m.Ecc[i] = 0 // the only vertex reaches everything at distance 0
+		}
+		total += sum
+		m.Pairs += reached
+		switch {
+		case reached == 0:
+			m.Closeness[i] = 0
+		case sum <= 0:
+			m.Closeness[i] = math.NaN()
+		default:
+			r := float64(reached)
+			m.Closeness[i] = r / float64(n-1) * r / sum
+		}
+		m.Radius = math.Min(m.Radius, m.Ecc[i])
+		m.Diameter = math.Max(m.Diameter, m.Ecc[i])
+	}
+	if m.Pairs > 0 {
+		m.AvgLength = total / float64(m.Pairs)
+	} else {
+		m.Longest = 0
+	}
+	for i := 0; i < n; i++ {
+		if m.Ecc[i] == m.Radius {
+			m.Roles[i] |= roleCenter
+		}
+		if m.Ecc[i] == m.Diameter {
+			m.Roles[i] |= rolePeriphery
+		}
+	}
+	return m
+}
+
+// vertices lists the (0-based) vertices that have role r.
+func (m graphMetrics) vertices(r vertexRole) []int {
+	var out []int
+	for i, got := range m.Roles {
+		if got&r != 0 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func metricText(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "∞"
+	case math.IsNaN(v):
+		return "—"
+	}
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+func vertexList(vs []int) string {
+	if len(vs) == 0 {
+		return "—"
+	}
+	names := make([]string, len(vs))
+	for k, v := range vs {
+		names[k] = vertexName(v)
+	}
+	return strings.Join(names, ", ")
+}
+
+// metricsPanel shows graphMetrics as a summary and a per-vertex table.
+type metricsPanel struct {
+	box     fyne.CanvasObject
+	summary *widget.Label
+	table   *widget.Table
+	mark    *widget.Check
+
+	m graphMetrics
+
+	onMark func() // the canvas marking was switched on or off
+}
+
+const (
+	metColVertex = iota
+	metColEcc
+	metColCloseness
+	metColRole
+	metCols
+)
+
+func newMetricsPanel() *metricsPanel {
+	p := &metricsPanel{summary: widget.NewLabel("")}
+	p.summary.Wrapping = fyne.TextWrapWord
+	p.table = widget.NewTable(
+		func() (int, int) { return len(p.m.Ecc), metCols },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			lbl := co.(*widget.Label)
+			if id.Row >= len(p.m.Ecc) {
+				lbl.SetText("")
+				return
+			}
+			var txt string
+			switch id.Col {
+			case metColVertex:
+				txt = vertexName(id.Row)
+			case metColEcc:
+				txt = metricText(p.m.Ecc[id.Row])
+			case metColCloseness:
+				txt = metricText(p.m.Closeness[id.Row])
+			case metColRole:
+				var roles []string
+				if p.m.Roles[id.Row]&roleCenter != 0 {
+					roles = append(roles, "центр")
+				}
+				if p.m.Roles[id.Row]&rolePeriphery != 0 {
+					roles = append(roles, "периферия")
+				}
+				txt = strings.Join(roles, ", ")
+			}
+			lbl.SetText(txt)
+		},
+	)
+	p.table.ShowHeaderRow = true
+	p.table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	p.table.UpdateHeader = func(id widget.TableCellID, co fyne.CanvasObject) {
+		titles := [metCols]string{"Вершина", "Эксцентриситет", "Близость", "Роль"}
+		if id.Col >= 0 && id.Col < metCols {
+			co.(*widget.Label).SetText(titles[id.Col])
+		}
+	}
+	p.table.SetColumnWidth(metColVertex, 80)
+	p.table.SetColumnWidth(metColEcc, 130)
+	p.table.SetColumnWidth(metColCloseness, 100)
+	p.table.SetColumnWidth(metColRole, 160)
+	p.mark = widget.NewCheck("Показать центр и периферию в редакторе", func(bool) {
+		if p.onMark != nil {
+			p.onMark()
+		}
+	})
+	p.box = container.NewBorder(container.NewVBox(p.summary, p.mark), nil, nil, nil, p.table)
+	return p
+}
+
+// Update shows the metrics of res, or err when there is no usable result.
+func (p *metricsPanel) Update(res *APSPResult, err error) {
+	if err == nil && res.N() == 0 {
+		err = fmt.Errorf("граф пуст")
+	}
+	if err != nil {
+		p.m = graphMetrics{}
+		p.summary.SetText(fmt.Sprintf("Метрики недоступны: %v", err))
+		p.table.Refresh()
+		return
+	}
+	p.m = computeMetrics(res)
+	m := p.m
+	lines := []string{
+		fmt.Sprintf("Радиус: %s, центр: %s", metricText(m.Radius), vertexList(m.vertices(roleCenter))),
+		fmt.Sprintf("Диаметр: %s, периферия: %s", metricText(m.Diameter), vertexList(m.vertices(rolePeriphery))),
+	}
+	if m.LongestPath != nil {
+		what := "Путь диаметра"
+		if math.IsInf(m.Diameter, 1) {
+			what = "Граф не сильно связен; наибольшее конечное расстояние"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", what, metricText(m.Longest), joinPathInts(m.LongestPath)))
+	}
+	if m.Pairs > 0 {
+		lines = append(lines, fmt.Sprintf("Средняя длина кратчайшего пути: %s (по %d достижимым парам)", metricText(m.AvgLength), m.Pairs))
+	}
+	p.summary.SetText(strings.Join(lines, "\n"))
+	p.table.Refresh()
+}
+
+// Roles returns the roles to mark on the canvas, nil when marking is off.
+func (p *metricsPanel) Roles() []vertexRole {
+	if !p.mark.Checked {
+		return nil
+	}
+	return p.m.Roles
+}
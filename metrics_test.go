@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	inf := math.Inf(1)
+	cases := []struct {
+		name              string
+		rows              []string
+		ecc               []float64
+		radius, diameter  float64
+		center, periphery []int
+	}{
+		{"single vertex", []string{"0"}, []float64{0}, 0, 0, []int{0}, []int{0}},
+		{"no edges", []string{"0;", ";0"}, []float64{inf, inf}, inf, inf, []int{0, 1}, []int{0, 1}},
+		{"all distances negative", []string{"0;-2;-5", ";0;-1", ";;0"},
+			[]float64{-2, inf, inf}, -2, inf, []int{0}, []int{1, 2}},
+		{"negative eccentricity", []string{"0;-3", "4;0"}, []float64{-3, 4}, -3, 4, []int{0}, []int{1}},
+		{"negative edge, strongly connected", []string{"0;-1;", "3;0;2", "1;;0"},
+			[]float64{1, 3, 1}, 1, 3, []int{0, 2}, []int{1}},
+		{"one vertex unreachable", []string{"0;2;", "2;0;", "1;;0"},
+			[]float64{inf, inf, 3}, 3, inf, []int{2}, []int{0, 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := solveFloyd(matrixGraph(t, c.rows...), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := computeMetrics(res)
+			if !slices.Equal(m.Ecc, c.ecc) {
+				t.Errorf("eccentricities %v, want %v", m.Ecc, c.ecc)
+			}
+			if m.Radius != c.radius || m.Diameter != c.diameter {
+				t.Errorf("radius %v, diameter %v, want %v, %v", m.Radius, m.Diameter, c.radius, c.diameter)
+			}
+			if got := m.vertices(roleCenter); !slices.Equal(got, c.center) {
+				t.Errorf("center %v, want %v", got, c.center)
+			}
+			if got := m.vertices(rolePeriphery); !slices.Equal(got, c.periphery) {
+				t.Errorf("periphery %v, want %v", got, c.periphery)
+			}
+		})
+	}
+}
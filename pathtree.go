@@ -0,0 +1,87 @@
+package main
+
+// ---------------- Shortest-path trees ----------------
+
+// pathTree is a single-source shortest-path tree, or a single-target one
+// when Reverse is set: then Dist[v] is the v → Root distance and Parent[v]
+// the next vertex on the way to Root.
+type pathTree struct {
+	Root    int
+	Reverse bool
+	Algo    string // "Дейкстра" or "Беллман–Форд"
+	Dist    []float64
+	Parent  []int // -1 when not connected to Root, Root for Root itself
+}
+
+// Reversed returns g with every edge turned around; labels and layout are
+// not copied.
+func (g *Graph) Reversed() *Graph {
+	r := NewGraph()
+	r.Resize(g.n)
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if w, ok := g.Edge(i, j); ok {
+				r.SetEdge(j, i, w, false)
+			}
+		}
+	}
+	return r
+}
+
+// shortestPathTree runs Dijkstra from root, or Bellman–Ford when g has
+// negative edges; reverse builds the tree of paths into root on the reversed
+// graph.
+func shortestPathTree(g *Graph, root int, reverse bool) (pathTree, error) {
+	h := g
+	if reverse {
+		h = g.Reversed()
+	}
+	t := pathTree{Root: root, Reverse: reverse, Algo: "Дейкстра"}
+	if h.HasNegativeEdge() {
+		var neg bool
+		t.Algo = "Беллман–Форд"
+		t.Dist, t.Parent, neg = h.bellmanFordFrom(root, nil)
+		if neg {
+			return pathTree{}, errNegativeCycle
+		}
+	} else {
+		t.Dist, t.Parent = h.dijkstraFrom(root, nil)
+	}
+	return t, nil
+}
+
+// Reached counts the vertices connected to the root, the root included.
+func (t pathTree) Reached() int {
+	k := 0
+	for _, p := range t.Parent {
+		if p >= 0 {
+			k++
+		}
+	}
+	return k
+}
+
+// Edges lists the tree edges as edges of the original graph.
+func (t pathTree) Edges() [][2]int {
+	var out [][2]int
+	for v, p := range t.Parent {
+		if p < 0 || v == t.Root {
+			continue
+		}
+		if t.Reverse {
+			out = append(out, [2]int{v, p})
+		} else {
+			out = append(out, [2]int{p, v})
+		}
+	}
+	return out
+}
+
+// Badges formats the distances shown next to the vertices.
+func (t pathTree) Badges() []string {
+	out := make([]string, len(t.Dist))
+	for v := range out {
+		out[v] = distText(t.Dist[v], t.Parent[v] >= 0)
+	}
+	return out
+}
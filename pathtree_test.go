@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkTargetTree compares the tree of paths into root with the Floyd
+// distances v → root and walks every tree path along edges of g.
+func checkTargetTree(t *testing.T, g *Graph, root int) {
+	t.Helper()
+	tree, err := shortestPathTree(g, root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := solveFloyd(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < g.n; v++ {
+		d, ok := res.Dist(v, root)
+		if ok != (tree.Parent[v] >= 0) {
+			t.Fatalf("%d → %d: reachable %v, tree parent %d", v+1, root+1, ok, tree.Parent[v])
+		}
+		if !ok {
+			continue
+		}
+		if tree.Dist[v] != d {
+			t.Fatalf("%d → %d: tree %v, Floyd %v", v+1, root+1, tree.Dist[v], d)
+		}
+		sum := 0.0
+		for u := v; u != root; u = tree.Parent[u] {
+			w, has := g.Edge(u, tree.Parent[u])
+			if !has {
+				t.Fatalf("tree edge %d → %d is not in the graph", u+1, tree.Parent[u]+1)
+			}
+			sum += w
+		}
+		if sum != d {
+			t.Fatalf("%d → %d: tree path weighs %v, want %v", v+1, root+1, sum, d)
+		}
+	}
+	for _, e := range tree.Edges() {
+		if !g.HasEdge(e[0], e[1]) {
+			t.Fatalf("Edges() gives %d → %d, not in the graph", e[0]+1, e[1]+1)
+		}
+	}
+}
+
+func TestTargetTreeOnReversedGraph(t *testing.T) {
+	cases := []struct {
+		name  string
+		n     int
+		edges []testEdge
+		root  int
+		algo  string
+	}{
+		{"chain into root", 4, []testEdge{{0, 1, 1}, {1, 2, 2}, {2, 3, 3}}, 3, "Дейкстра"},
+		{"root is a source", 3, []testEdge{{0, 1, 1}, {0, 2, 1}}, 0, "Дейкстра"},
+		{"shortcut", 3, []testEdge{{0, 2, 5}, {0, 1, 1}, {1, 2, 1}}, 2, "Дейкстра"},
+		{"negative edge", 4, []testEdge{{0, 1, 4}, {0, 2, 1}, {1, 3, 1}, {2, 1, -3}, {3, 0, 2}}, 3, "Беллман–Форд"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := edgesGraph(c.n, c.edges)
+			checkTargetTree(t, g, c.root)
+			if tree, _ := shortestPathTree(g, c.root, true); tree.Algo != c.algo {
+				t.Errorf("algo %s, want %s", tree.Algo, c.algo)
+			}
+		})
+	}
+
+	rng := rand.New(rand.NewSource(48))
+	for round := 0; round < 30; round++ {
+		n := 1 + rng.Intn(10)
+		g := randomGraph(rng, n, 0.25, func() float64 { return float64(rng.Intn(9)) })
+		checkTargetTree(t, g, rng.Intn(n))
+	}
+}
+
+func TestTargetTreeNegativeCycle(t *testing.T) {
+	g := edgesGraph(3, []testEdge{{0, 1, 1}, {1, 0, -2}, {2, 0, 1}})
+	if _, err := shortestPathTree(g, 0, true); err != errNegativeCycle {
+		t.Errorf("err = %v, want errNegativeCycle", err)
+	}
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Results export ----------------
+
+// exportOptions configures how results are written. DecimalComma writes
+// "1,5" instead of "1.5", which is what Excel expects under a Russian locale.
+type exportOptions struct {
+	Format       string // csv, json, md or html
+	Sep          rune   // CSV field delimiter
+	DecimalComma bool
+	BOM          bool // UTF-8 byte order mark, lets Excel detect the encoding
+}
+
+type exportFormat struct {
+	Key   string
+	Title string
+	Ext   string
+}
+
+var exportFormats = []exportFormat{
+	{Key: "csv", Title: "CSV (таблица)", Ext: ".csv"},
+	{Key: "json", Title: "JSON", Ext: ".json"},
+	{Key: "md", Title: "Отчёт Markdown", Ext: ".md"},
+	{Key: "html", Title: "Отчёт HTML", Ext: ".html"},
+}
+
+func exportFormatByKey(key string) (exportFormat, bool) {
+	for _, f := range exportFormats {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return exportFormat{}, false
+}
+
+func exportFormatByTitle(title string) exportFormat {
+	for _, f := range exportFormats {
+		if f.Title == title {
+			return f
+		}
+	}
+	return exportFormats[0]
+}
+
+func (o exportOptions) num(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if o.DecimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// dist formats a distance or weight; absent values become inf (CSV) or ∞.
+func (o exportOptions) dist(d float64, ok bool) string {
+	if !ok || math.IsInf(d, 0) {
+		s := "∞"
+		if o.Format == "csv" {
+			s = "inf"
+		}
+		if ok && d < 0 {
+			s = "-" + s
+		}
+		return s
+	}
+	return o.num(d)
+}
+
+// resDist formats the i → j distance of res, exactly for exact-weight results.
+func (o exportOptions) resDist(res *APSPResult, i, j int) string {
+	if res.Weights == "" || !res.Reachable(i, j) {
+		return o.dist(res.Dist(i, j))
+	}
+	s := res.DistText(i, j)
+	if o.DecimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// writeResults writes res in the format chosen by o. g is the input graph and
+// is only used by the reports.
+func writeResults(out io.Writer, g *Graph, res *APSPResult, o exportOptions) error {
+	if res.NegCycle {
+		return errNegativeCycle
+	}
+	switch o.Format {
+	case "csv", "":
+		return writeResultsCSV(out, res, o)
+	case "json":
+		return writeResultsJSON(out, res)
+	case "md", "html":
+		return writeResultsReport(out, g, res, o)
+	}
+	return fmt.Errorf("неизвестный формат экспорта %q", o.Format)
+}
+
+// writeResultsCSV writes all-pairs results in the "i;j;length;path" layout.
+func writeResultsCSV(out io.Writer, res *APSPResult, o exportOptions) error {
+	bw := bufio.NewWriter(out)
+	if o.BOM {
+		bw.WriteString("\uFEFF")
+	}
+	wrt := csv.NewWriter(bw)
+	wrt.Comma = o.Sep
+	wrt.Write([]string{"i", "j", "length", "path"})
+	n := res.N()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			ok := res.Reachable(i, j)
+			p := res.Path(i, j)
+			pathStr := ""
+			if len(p) == 0 && ok {
+				pathStr = "-"
+			} else if len(p) > 0 {
+				parts := make([]string, len(p))
+				for k, v := range p {
+					parts[k] = strconv.Itoa(v)
+				}
+				pathStr = strings.Join(parts, " ")
+			}
+			wrt.Write([]string{strconv.Itoa(i + 1), strconv.Itoa(j + 1), o.resDist(res, i, j), pathStr})
+		}
+	}
+	wrt.Flush()
+	if err := wrt.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+type resultsDoc struct {
+	Algo      string       `json:"algo"`
+	Weights   string       `json:"weights,omitempty"`
+	Semiring  string       `json:"semiring,omitempty"`
+	Exact     [][]*string  `json:"exact,omitempty"` // exact distances as text, e.g. "1/3"
+	N         int          `json:"n"`
+	ElapsedMS float64      `json:"elapsed_ms"`
+	Stats     SolverStats  `json:"stats"`
+	Dist      [][]*float64 `json:"dist"`
+	Paths     []pathDoc    `json:"paths"`
+}
+
+// writeResultsJSON writes the distance matrix (null = unreachable) and every
+// pair with its path as an array of 1-based vertex numbers.
+func writeResultsJSON(out io.Writer, res *APSPResult) error {
+	n := res.N()
+	doc := resultsDoc{Algo: res.Algo, N: n, ElapsedMS: float64(res.Elapsed.Microseconds()) / 1000, Stats: res.Stats,
+		Dist: make([][]*float64, n), Paths: []pathDoc{}, Weights: res.Weights, Semiring: res.Paths}
+	if res.Weights != "" {
+		doc.Exact = make([][]*string, n)
+	}
+	for i := 0; i < n; i++ {
+		doc.Dist[i] = make([]*float64, n)
+		for j := 0; j < n; j++ {
+			d, ok := res.Dist(i, j)
+			doc.Dist[i][j] = jsonDist(d, ok)
+			if doc.Exact != nil {
+				if doc.Exact[i] == nil {
+					doc.Exact[i] = make([]*string, n)
+				}
+				if ok {
+					t := res.DistText(i, j)
+					doc.Exact[i][j] = &t
+				}
+			}
+			if i == j {
+				continue
+			}
+			p := pathDoc{From: i + 1, To: j + 1, Reachable: ok, Length: doc.Dist[i][j], Path: []int{}}
+			if ok {
+				p.Path = res.Path(i, j)
+			}
+			doc.Paths = append(doc.Paths, p)
+		}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// reportTable is a table shared by the Markdown and HTML renderers.
+type reportTable struct {
+	Title string
+	Head  []string
+	Rows  [][]string
+}
+
+func matrixTable(title string, n int, cell func(i, j int) string) reportTable {
+	t := reportTable{Title: title, Head: []string{"i/j"}}
+	for j := 0; j < n; j++ {
+		t.Head = append(t.Head, vertexName(j))
+	}
+	for i := 0; i < n; i++ {
+		row := []string{vertexName(i)}
+		for j := 0; j < n; j++ {
+			row = append(row, cell(i, j))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func writeResultsReport(out io.Writer, g *Graph, res *APSPResult, o exportOptions) error {
+	n := res.N()
+	title := res.Algo
+	if sv, ok := solverByKey(res.Algo); ok {
+		title = sv.Title
+	}
+	summary := []string{
+		fmt.Sprintf("Алгоритм: %s", title),
+		fmt.Sprintf("Арифметика: %s", weightTitle(res.Weights)),
+		fmt.Sprintf("Пути: %s", semiringTitle(res.Paths)),
+		fmt.Sprintf("Вершин: %d", n),
+		fmt.Sprintf("Время: %s", res.Elapsed.Round(time.Microsecond)),
+		fmt.Sprintf("Счётчики: %s", res.Stats.String()),
+	}
+	tables := []reportTable{
+		matrixTable("Матрица весов", n, func(i, j int) string {
+			if i == j {
+				return "0"
+			}
+			return o.dist(g.Edge(i, j))
+		}),
+		matrixTable("Матрица расстояний", n, func(i, j int) string { return o.resDist(res, i, j) }),
+	}
+	sr := semiringOf(res)
+	paths := reportTable{Title: sr.Report, Head: []string{"Из", "В", sr.Value, "Путь"}}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			p := "пути нет"
+			if res.Reachable(i, j) {
+				p = joinPathInts(res.Path(i, j))
+			}
+			paths.Rows = append(paths.Rows, []string{vertexName(i), vertexName(j), o.resDist(res, i, j), p})
+		}
+	}
+	tables = append(tables, paths)
+
+	bw := bufio.NewWriter(out)
+	if o.Format == "html" {
+		renderHTMLReport(bw, sr.Report, summary, tables)
+	} else {
+		renderMarkdownReport(bw, sr.Report, summary, tables)
+	}
+	return bw.Flush()
+}
+
+func renderMarkdownReport(w *bufio.Writer, title string, summary []string, tables []reportTable) {
+	fmt.Fprintf(w, "# %s между всеми парами вершин\n\n", title)
+	for _, s := range summary {
+		fmt.Fprintf(w, "- %s\n", s)
+	}
+	esc := strings.NewReplacer("|", `\|`, "\n", " ")
+	row := func(cells []string) {
+		w.WriteString("|")
+		for _, c := range cells {
+			w.WriteString(" " + esc.Replace(c) + " |")
+		}
+		w.WriteString("\n")
+	}
+	for _, t := range tables {
+		fmt.Fprintf(w, "\n## %s\n\n", t.Title)
+		row(t.Head)
+		w.WriteString("|" + strings.Repeat(" --- |", len(t.Head)) + "\n")
+		for _, r := range t.Rows {
+			row(r)
+		}
+	}
+}
+
+func renderHTMLReport(w *bufio.Writer, title string, summary []string, tables []reportTable) {
+	title = html.EscapeString(title)
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #999; padding: 0.25em 0.6em; text-align: right; }
+th { background: #eee; }
+</style>
+</head>
+<body>
+<h1>%s между всеми парами вершин</h1>
+<ul>
+`, title, title)
+	for _, s := range summary {
+		fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(s))
+	}
+	w.WriteString("</ul>\n")
+	for _, t := range tables {
+		fmt.Fprintf(w, "<h2>%s</h2>\n<table>\n<tr>", html.EscapeString(t.Title))
+		for _, h := range t.Head {
+			fmt.Fprintf(w, "<th>%s</th>", html.EscapeString(h))
+		}
+		w.WriteString("</tr>\n")
+		for _, r := range t.Rows {
+			w.WriteString("<tr>")
+			for _, c := range r {
+				fmt.Fprintf(w, "<td>%s</td>", html.EscapeString(c))
+			}
+			w.WriteString("</tr>\n")
+		}
+		w.WriteString("</table>\n")
+	}
+	w.WriteString("</body>\n</html>\n")
+}
+
+var exportSeps = []struct {
+	Title string
+	Sep   rune
+}{
+	{"; (точка с запятой)", ';'},
+	{", (запятая)", ','},
+	{"табуляция", '\t'},
+}
+
+// showExportDialog asks for the solver and the output options, then saves
+// the (cached) result of that solver.
+func showExportDialog(w fyne.Window, g *Graph, cache *apspCache) {
+	var solverTitles, formatTitles, sepTitles []string
+	for _, sv := range apspSolvers {
+		solverTitles = append(solverTitles, sv.Title)
+	}
+	for _, f := range exportFormats {
+		formatTitles = append(formatTitles, f.Title)
+	}
+	for _, s := range exportSeps {
+		sepTitles = append(sepTitles, s.Title)
+	}
+	solverSel := widget.NewSelect(solverTitles, nil)
+	solverSel.SetSelectedIndex(0)
+	var weightTitles []string
+	for _, k := range weightKinds {
+		weightTitles = append(weightTitles, k.Title)
+	}
+	weightSel := widget.NewSelect(weightTitles, nil)
+	weightSel.SetSelectedIndex(0)
+	var pathTitles []string
+	for _, s := range pathSemirings {
+		pathTitles = append(pathTitles, s.Title)
+	}
+	pathSel := widget.NewSelect(pathTitles, nil)
+	pathSel.SetSelectedIndex(0)
+	sepSel := widget.NewSelect(sepTitles, nil)
+	sepSel.SetSelectedIndex(0)
+	commaCheck := widget.NewCheck("десятичная запятая (Excel, русская локаль)", nil)
+	bomCheck := widget.NewCheck("UTF-8 BOM (для Excel)", nil)
+	formatSel := widget.NewSelect(formatTitles, nil)
+	formatSel.OnChanged = func(s string) {
+		if exportFormatByTitle(s).Key == "csv" {
+			sepSel.Enable()
+			bomCheck.Enable()
+		} else {
+			sepSel.Disable()
+			bomCheck.Disable()
+		}
+		if exportFormatByTitle(s).Key == "json" {
+			commaCheck.Disable()
+		} else {
+			commaCheck.Enable()
+		}
+	}
+	formatSel.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Алгоритм", solverSel),
+		widget.NewFormItem("Арифметика", weightSel),
+		widget.NewFormItem("Пути", pathSel),
+		widget.NewFormItem("Формат", formatSel),
+		widget.NewFormItem("Разделитель", sepSel),
+		widget.NewFormItem("", commaCheck),
+		widget.NewFormItem("", bomCheck),
+	}
+	dialog.ShowForm("Экспорт результатов", "Сохранить…", "Отмена", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		sv := apspSolvers[solverSel.SelectedIndex()]
+		f := exportFormatByTitle(formatSel.Selected)
+		opts := exportOptions{Format: f.Key, Sep: exportSeps[sepSel.SelectedIndex()].Sep,
+			DecimalComma: commaCheck.Checked && f.Key != "json", BOM: bomCheck.Checked}
+		key := solverCacheKey(sv.Key, weightKinds[weightSel.SelectedIndex()].Key, pathSemirings[pathSel.SelectedIndex()].Key)
+		res, _, err := cache.Get(key)
+		if res != nil && res.NegCycle {
+			dialog.ShowError(fmt.Errorf("Отрицательный цикл — экспорт невозможен"), w)
+			return
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		d := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+			if err != nil || uc == nil {
+				return
+			}
+			defer uc.Close()
+			if e := writeResults(uc, g, res, opts); e != nil {
+				dialog.ShowError(e, w)
+			} else {
+				dialog.ShowInformation("Готово", "Результаты сохранены ("+f.Title+")", w)
+			}
+		}, w)
+		d.SetFileName("apsp-" + strings.NewReplacer(":", "-", "+", "-").Replace(key) + f.Ext)
+		d.Show()
+	}, w)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportFixture solves 1 → 2 → 3 with fractional weights; 3 reaches nothing.
+func exportFixture(t *testing.T) (*Graph, *APSPResult) {
+	t.Helper()
+	g := edgesGraph(3, []testEdge{{0, 1, 1.5}, {1, 2, 0.25}, {0, 2, 2}})
+	sv, _ := solverByKey("floyd")
+	res, err := runSolver(sv, "", "", g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// counters depend on the Floyd variant, the report layout does not
+	res.Elapsed, res.Stats = 1500*time.Microsecond, SolverStats{}
+	return g, res
+}
+
+func TestWriteResultsGolden(t *testing.T) {
+	g, res := exportFixture(t)
+	cases := []struct {
+		name string
+		o    exportOptions
+		want string
+	}{
+		{"csv decimal comma and bom", exportOptions{Format: "csv", Sep: ';', DecimalComma: true, BOM: true}, "\uFEFF" + `i;j;length;path
+1;2;1,5;1 2
+1;3;1,75;1 2 3
+2;1;inf;
+2;3;0,25;2 3
+3;1;inf;
+3;2;inf;
+`},
+		{"markdown", exportOptions{Format: "md"}, `# Кратчайшие пути между всеми парами вершин
+
+- Алгоритм: Флойд
+- Арифметика: float64
+- Пути: кратчайший (min, +)
+- Вершин: 3
+- Время: 1.5ms
+- Счётчики: итераций 0, релаксаций 0 (успешных 0), сравнений 0, операций с кучей 0
+
+## Матрица весов
+
+| i/j | 1 | 2 | 3 |
+| --- | --- | --- | --- |
+| 1 | 0 | 1.5 | 2 |
+| 2 | ∞ | 0 | 0.25 |
+| 3 | ∞ | ∞ | 0 |
+
+## Матрица расстояний
+
+| i/j | 1 | 2 | 3 |
+| --- | --- | --- | --- |
+| 1 | 0 | 1.5 | 1.75 |
+| 2 | ∞ | 0 | 0.25 |
+| 3 | ∞ | ∞ | 0 |
+
+## Кратчайшие пути
+
+| Из | В | Длина | Путь |
+| --- | --- | --- | --- |
+| 1 | 2 | 1.5 | 1 → 2 |
+| 1 | 3 | 1.75 | 1 → 2 → 3 |
+| 2 | 1 | ∞ | пути нет |
+| 2 | 3 | 0.25 | 2 → 3 |
+| 3 | 1 | ∞ | пути нет |
+| 3 | 2 | ∞ | пути нет |
+`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeResults(&buf, g, res, c.o); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReportTitleFollowsSemiring(t *testing.T) {
+	g, _ := exportFixture(t)
+	sv, _ := solverByKey("floyd")
+	res, err := runSolver(sv, "", "widest", g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		format string
+		want   []string
+	}{
+		{"md", []string{"# Наиболее широкие пути между всеми парами вершин\n", "## Наиболее широкие пути\n", "| Из | В | Ширина | Путь |"}},
+		{"html", []string{"<title>Наиболее широкие пути</title>", "<h1>Наиболее широкие пути между всеми парами вершин</h1>", "<th>Ширина</th>"}},
+	} {
+		var buf bytes.Buffer
+		if err := writeResults(&buf, g, res, exportOptions{Format: c.format}); err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range c.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("%s: no %q in\n%s", c.format, w, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "Кратчайшие") {
+			t.Errorf("%s: report still says shortest paths", c.format)
+		}
+	}
+}
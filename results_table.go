@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ---------------- Results list (sortable, filterable) ----------------
+
+const (
+	colI = iota
+	colJ
+	colLength
+	colHops
+	colPath
+	resCols
+)
+
+type resRow struct {
+	I, J   int     // 0-based vertices
+	Reach  bool    // j is reachable from i
+	Length float64 // meaningful only when Reach
+	Path   []int   // 1-based path, nil when unknown
+	Text   string  // exact length, "" to format Length
+}
+
+func (r resRow) reachable() bool { return r.Reach }
+
+// hops is the number of edges on the path, -1 when there is no path.
+func (r resRow) hops() int {
+	if !r.reachable() || len(r.Path) == 0 {
+		return -1
+	}
+	return len(r.Path) - 1
+}
+
+func (r resRow) lengthText() string {
+	if r.Text != "" {
+		return r.Text
+	}
+	return distText(r.Length, r.Reach)
+}
+
+func (r resRow) pathText() string {
+	if !r.reachable() {
+		return "пути нет"
+	}
+	if len(r.Path) == 0 {
+		return "-"
+	}
+	return joinPathInts(r.Path)
+}
+
+type resultsList struct {
+	table *widget.Table
+	bar   fyne.CanvasObject
+
+	all   []resRow
+	shown []resRow
+	n     int
+
+	sortCol  int
+	sortDesc bool
+
+	valueTitle string // header of the length column
+	valueLabel *widget.Label
+
+	from, to       *widget.Select
+	reachableOnly  *widget.Check
+	minLen, maxLen *widget.Entry
+	search         *widget.Entry
+	count          *widget.Label
+
+	onPick func(r resRow)
+}
+
+const anyVertex = "все"
+
+func newResultsList() *resultsList {
+	l := &resultsList{sortCol: -1, valueTitle: "Длина", valueLabel: widget.NewLabel("Длина:")}
+	l.table = widget.NewTable(
+		func() (int, int) { return len(l.shown), resCols },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, co fyne.CanvasObject) {
+			if id.Row >= len(l.shown) {
+				co.(*widget.Label).SetText("")
+				return
+			}
+			r := l.shown[id.Row]
+			var txt string
+			switch id.Col {
+			case colI:
+				txt = vertexName(r.I)
+			case colJ:
+				txt = vertexName(r.J)
+			case colLength:
+				txt = r.lengthText()
+			case colHops:
+				if h := r.hops(); h >= 0 {
+					txt = strconv.Itoa(h)
+				}
+			case colPath:
+				txt = r.pathText()
+			}
+			co.(*widget.Label).SetText(txt)
+		},
+	)
+	l.table.OnSelected = func(id widget.TableCellID) {
+		if id.Row < 0 || id.Row >= len(l.shown) || l.onPick == nil {
+			return
+		}
+		l.onPick(l.shown[id.Row])
+	}
+	l.table.ShowHeaderRow = true
+	l.table.CreateHeader = func() fyne.CanvasObject {
+		b := widget.NewButton("", nil)
+		b.Importance = widget.LowImportance
+		return b
+	}
+	l.table.UpdateHeader = func(id widget.TableCellID, co fyne.CanvasObject) {
+		b := co.(*widget.Button)
+		titles := [resCols]string{"i", "j", l.valueTitle, "Рёбер", "Путь"}
+		if id.Col < 0 || id.Col >= resCols {
+			b.SetText("")
+			return
+		}
+		title := titles[id.Col]
+		if id.Col == l.sortCol {
+			if l.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		b.SetText(title)
+		if id.Col == colPath {
+			b.OnTapped = nil
+			return
+		}
+		col := id.Col
+		b.OnTapped = func() { l.sortBy(col) }
+	}
+	l.table.SetColumnWidth(colI, 48)
+	l.table.SetColumnWidth(colJ, 48)
+	l.table.SetColumnWidth(colLength, 92)
+	l.table.SetColumnWidth(colHops, 72)
+	l.table.SetColumnWidth(colPath, 360)
+
+	l.from = widget.NewSelect([]string{anyVertex}, nil)
+	l.from.SetSelected(anyVertex)
+	l.to = widget.NewSelect([]string{anyVertex}, nil)
+	l.to.SetSelected(anyVertex)
+	l.reachableOnly = widget.NewCheck("только достижимые", nil)
+	l.minLen = widget.NewEntry()
+	l.minLen.SetPlaceHolder("от")
+	l.maxLen = widget.NewEntry()
+	l.maxLen.SetPlaceHolder("до")
+	l.search = widget.NewEntry()
+	l.search.SetPlaceHolder("поиск по пути, например 2 → 5")
+	l.count = widget.NewLabel("")
+
+	refilter := func(string) { l.apply() }
+	l.from.OnChanged = refilter
+	l.to.OnChanged = refilter
+	l.reachableOnly.OnChanged = func(bool) { l.apply() }
+	l.minLen.OnChanged = refilter
+	l.maxLen.OnChanged = refilter
+	l.search.OnChanged = refilter
+
+	l.bar = container.NewVBox(
+		container.NewHBox(
+			widget.NewLabel("Из:"), l.from,
+			widget.NewLabel("В:"), l.to,
+			l.reachableOnly,
+		),
+		container.NewBorder(nil, nil,
+			container.NewHBox(l.valueLabel, sizedEntry(l.minLen, 72), sizedEntry(l.maxLen, 72)),
+			l.count, l.search),
+	)
+	return l
+}
+
+// sizedEntry gives an entry a fixed width inside a box layout.
+func sizedEntry(e *widget.Entry, width float32) fyne.CanvasObject {
+	return container.NewGridWrap(fyne.NewSize(width, e.MinSize().Height), e)
+}
+
+// SetRows replaces the result set; sorting and filters are kept as they are.
+func (l *resultsList) SetRows(rows []resRow, n int) {
+	l.all = rows
+	if n != l.n {
+		l.n = n
+		opts := make([]string, 0, n+1)
+		opts = append(opts, anyVertex)
+		for i := 0; i < n; i++ {
+			opts = append(opts, vertexName(i))
+		}
+		for _, s := range []*widget.Select{l.from, l.to} {
+			sel := s.Selected
+			s.Options = opts
+			if vertexIndex(sel, n) < 0 {
+				sel = anyVertex
+			}
+			s.SetSelected(sel)
+		}
+	}
+	l.apply()
+}
+
+// SetValueTitle renames the length column, e.g. for widest paths.
+func (l *resultsList) SetValueTitle(t string) {
+	if t == l.valueTitle {
+		return
+	}
+	l.valueTitle = t
+	l.valueLabel.SetText(t + ":")
+	l.table.Refresh()
+}
+
+// SetSource filters the list to paths starting at v; -1 clears the filter.
+func (l *resultsList) SetSource(v int) {
+	if v < 0 || v >= l.n {
+		l.from.SetSelected(anyVertex)
+		return
+	}
+	l.from.SetSelected(vertexName(v))
+}
+
+func (l *resultsList) sortBy(col int) {
+	if l.sortCol == col {
+		l.sortDesc = !l.sortDesc
+	} else {
+		l.sortCol, l.sortDesc = col, false
+	}
+	l.apply()
+}
+
+// vertexIndex maps a selector value back to a 0-based vertex, -1 for "все".
+func vertexIndex(s string, n int) int {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 || v > n {
+		return -1
+	}
+	return v - 1
+}
+
+func parseBound(s string, def float64) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil || math.IsNaN(v) {
+		return def
+	}
+	return v
+}
+
+func (l *resultsList) keep(r resRow) bool {
+	if from := vertexIndex(l.from.Selected, l.n); from >= 0 && r.I != from {
+		return false
+	}
+	if to := vertexIndex(l.to.Selected, l.n); to >= 0 && r.J != to {
+		return false
+	}
+	if l.reachableOnly.Checked && !r.reachable() {
+		return false
+	}
+	lo := parseBound(l.minLen.Text, math.Inf(-1))
+	hi := parseBound(l.maxLen.Text, math.Inf(1))
+	if !math.IsInf(lo, -1) || !math.IsInf(hi, 1) {
+		if !r.reachable() || r.Length < lo || r.Length > hi {
+			return false
+		}
+	}
+	if q := l.search.Text; strings.TrimSpace(q) != "" && (!r.reachable() || !pathMatches(r.Path, q)) {
+		return false
+	}
+	return true
+}
+
+// pathMatches reports whether query q, vertex numbers separated by spaces or
+// arrows ("2 → 5", "2->5", "2 5"), occurs as consecutive vertices of path.
+// Whole numbers are compared, so "1 → 2" does not match 11 → 2 or 1 → 21.
+func pathMatches(path []int, q string) bool {
+	var want []int
+	for _, f := range strings.Fields(strings.NewReplacer("->", " ", "→", " ").Replace(q)) {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return false
+		}
+		want = append(want, v)
+	}
+	for k := 0; k+len(want) <= len(path); k++ {
+		if slices.Equal(path[k:k+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *resultsList) less(a, b resRow) bool {
+	switch l.sortCol {
+	case colI:
+		return a.I < b.I
+	case colJ:
+		return a.J < b.J
+	case colLength:
+		if a.Reach != b.Reach {
+			return a.Reach
+		}
+		return a.Length < b.Length
+	case colHops:
+		ha, hb := a.hops(), b.hops()
+		if ha < 0 {
+			ha = math.MaxInt
+		}
+		if hb < 0 {
+			hb = math.MaxInt
+		}
+		return ha < hb
+	}
+	return false
+}
+
+func (l *resultsList) apply() {
+	shown := make([]resRow, 0, len(l.all))
+	for _, r := range l.all {
+		if l.keep(r) {
+			shown = append(shown, r)
+		}
+	}
+	if l.sortCol >= 0 {
+		sort.SliceStable(shown, func(x, y int) bool {
+			if l.sortDesc {
+				return l.less(shown[y], shown[x])
+			}
+			return l.less(shown[x], shown[y])
+		})
+	}
+	l.shown = shown
+	l.table.UnselectAll()
+	l.count.SetText(fmt.Sprintf("%d из %d", len(l.shown), len(l.all)))
+	l.table.Refresh()
+}
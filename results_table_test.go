@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestPathMatches(t *testing.T) {
+	cases := []struct {
+		path []int
+		q    string
+		want bool
+	}{
+		{[]int{1, 2, 5}, "1 → 2", true},
+		{[]int{1, 2, 5}, "2->5", true},
+		{[]int{1, 2, 5}, " 2  5 ", true},
+		{[]int{1, 2, 5}, "1 → 2 → 5", true},
+		{[]int{1, 2, 5}, "5", true},
+		{[]int{1, 2, 5}, "1 → 5", false},
+		{[]int{11, 2}, "1 → 2", false},
+		{[]int{1, 21}, "1 → 2", false},
+		{[]int{3, 11, 2}, "11 → 2", true},
+		{[]int{1, 2}, "1 → 2 → 3", false},
+		{[]int{1, 2}, "1 → x", false},
+		{nil, "1", false},
+	}
+	for _, c := range cases {
+		if got := pathMatches(c.path, c.q); got != c.want {
+			t.Errorf("pathMatches(%v, %q) = %v, want %v", c.path, c.q, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ---------------- Path semirings ----------------
+
+// pathSemiring gives path values a meaning for the Floyd–Warshall closure:
+// Extend joins the values of two consecutive parts of a path and Better
+// chooses between two alternative paths. One is the value of the empty path
+// (the diagonal); no cycle may beat it, or values become unbounded — which is
+// exactly a negative cycle for shortest paths.
+type pathSemiring struct {
+	Key    string
+	Title  string
+	Report string // plural name of the paths, for report headings
+	Value  string // what a path value is, for column titles
+	One    float64
+	Extend func(a, b float64) float64
+	Better func(a, b float64) bool
+	Check  func(w float64) error // nil accepts every edge weight
+}
+
+func lessFloat(a, b float64) bool    { return a < b }
+func greaterFloat(a, b float64) bool { return a > b }
+
+// checkProbability keeps reliabilities within [0, 1]; a factor above 1 would
+// let cycles improve a path forever.
+func checkProbability(w float64) error {
+	if w < 0 || w > 1 {
+		return fmt.Errorf("надёжность %g вне отрезка [0, 1]", w)
+	}
+	return nil
+}
+
+// pathSemirings lists the path semantics; the first one is the usual
+// shortest path, the only one the other solvers and exact arithmetic support.
+var pathSemirings = []pathSemiring{
+	{Key: "shortest", Title: "кратчайший (min, +)", Report: "Кратчайшие пути", Value: "Длина", One: 0,
+		Extend: func(a, b float64) float64 { return a + b }, Better: lessFloat},
+	{Key: "widest", Title: "наиболее широкий (max, min)", Report: "Наиболее широкие пути",
+		Value: "Ширина", One: math.Inf(1),
+		Extend: math.Min, Better: greaterFloat},
+	{Key: "reliable", Title: "наиболее надёжный (max, ×)", Report: "Наиболее надёжные пути",
+		Value: "Надёжность", One: 1,
+		Extend: func(a, b float64) float64 { return a * b }, Better: greaterFloat, Check: checkProbability},
+	{Key: "minimax", Title: "минимаксный (min, max)", Report: "Минимаксные пути", Value: "Наиб. дуга", One: math.Inf(-1),
+		Extend: math.Max, Better: lessFloat},
+}
+
+func semiringByKey(key string) (pathSemiring, bool) {
+	for _, s := range pathSemirings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return pathSemiring{}, false
+}
+
+// semiringTitle names a path semantics; "" means shortest paths.
+func semiringTitle(key string) string {
+	if s, ok := semiringByKey(key); ok {
+		return s.Title
+	}
+	return pathSemirings[0].Title
+}
+
+// semiringOf returns the path semantics res was computed with.
+func semiringOf(res *APSPResult) pathSemiring {
+	if s, ok := semiringByKey(res.Paths); ok {
+		return s
+	}
+	return pathSemirings[0]
+}
+
+func errUnknownSemiring(key string) error {
+	keys := make([]string, len(pathSemirings))
+	for i, s := range pathSemirings {
+		keys[i] = s.Key
+	}
+	return fmt.Errorf("неизвестный вид путей %q (доступны: %s)", key, strings.Join(keys, ", "))
+}
+
+var errSemiringSolver = errors.New("этот вид путей считается только алгоритмом Флойда в арифметике float64")
+
+// Closure is Floyd–Warshall over sr; st may be nil. improving reports a cycle
+// that beats the empty path, so some values are unbounded.
+func (g *Graph) Closure(sr pathSemiring, st *SolverStats) (dist Matrix[float64], pred Matrix[int], improving bool) {
+	dist, pred = floydInit(g)
+	if sr.Key == "shortest" {
+		// the specialised kernel avoids a function call per relaxation
+		floydBlocked(dist, pred, floydBlock, st)
+	} else {
+		for i := 0; i < g.n; i++ {
+			dist.Set(i, i, sr.One)
+		}
+		floydBlockedWith(dist, pred, floydBlock, sr, st)
+	}
+	for i := 0; i < g.n; i++ {
+		if sr.Better(dist.At(i, i), sr.One) {
+			return dist, pred, true
+		}
+	}
+	return dist, pred, false
+}
+
+// solveClosure runs the closure over semiring key after checking the weights.
+func solveClosure(g *Graph, key string, st *SolverStats) (*APSPResult, error) {
+	sr, ok := semiringByKey(key)
+	if !ok {
+		return nil, errUnknownSemiring(key)
+	}
+	if sr.Check != nil {
+		for i := 0; i < g.n; i++ {
+			for j := 0; j < g.n; j++ {
+				if w, ok := g.Edge(i, j); ok {
+					if err := sr.Check(w); err != nil {
+						return nil, fmt.Errorf("дуга %d → %d: %v", i+1, j+1, err)
+					}
+				}
+			}
+		}
+	}
+	dist, pred, neg := g.Closure(sr, st)
+	res := &APSPResult{dist: dist, pred: pred, NegCycle: neg}
+	if neg {
+		return res, errNegativeCycle
+	}
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestClosureSemirings solves 1 → 2 → 3 with a direct arc 1 → 3 under every
+// path semantics; the detour wins for the widest and most reliable paths.
+func TestClosureSemirings(t *testing.T) {
+	g := edgesGraph(3, []testEdge{{0, 1, 0.5}, {1, 2, 0.8}, {0, 2, 0.3}})
+	cases := []struct {
+		key  string
+		one  float64
+		d13  float64
+		path []int
+	}{
+		{"shortest", 0, 0.3, []int{1, 3}},
+		{"widest", math.Inf(1), 0.5, []int{1, 2, 3}},
+		{"reliable", 1, 0.4, []int{1, 2, 3}},
+		{"minimax", math.Inf(-1), 0.3, []int{1, 3}},
+	}
+	for _, c := range cases {
+		t.Run(c.key, func(t *testing.T) {
+			res, err := solveClosure(g, c.key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				if d, _ := res.Dist(i, i); d != c.one {
+					t.Errorf("diagonal %d = %v, want %v", i+1, d, c.one)
+				}
+			}
+			if d, ok := res.Dist(0, 1); !ok || d != 0.5 {
+				t.Errorf("1 → 2 = %v, %v, want 0.5", d, ok)
+			}
+			if d, ok := res.Dist(0, 2); !ok || d != c.d13 {
+				t.Errorf("1 → 3 = %v, %v, want %v", d, ok, c.d13)
+			}
+			if p := res.Path(0, 2); !slices.Equal(p, c.path) {
+				t.Errorf("path 1 → 3 = %v, want %v", p, c.path)
+			}
+			if res.Reachable(2, 0) {
+				t.Error("3 reaches 1")
+			}
+		})
+	}
+}
+
+func TestClosureSemiringErrors(t *testing.T) {
+	g := edgesGraph(2, []testEdge{{0, 1, 1.5}})
+	if _, err := solveClosure(g, "reliable", nil); err == nil || !strings.Contains(err.Error(), "дуга 1 → 2") {
+		t.Errorf("reliability 1.5: err = %v", err)
+	}
+	if _, err := solveClosure(g, "longest", nil); err == nil {
+		t.Error("unknown semiring accepted")
+	}
+	sv, _ := solverByKey("dijkstra")
+	if _, err := runSolver(sv, "", "widest", g); err != errSemiringSolver {
+		t.Errorf("widest with Dijkstra: err = %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ---------------- HTTP/JSON service ----------------
+
+type edgeDoc struct {
+	From   int     `json:"from"`
+	To     int     `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// graphDoc is the upload format: either n with an edge list (1-based), or a
+// weight matrix with null for "no edge".
+type graphDoc struct {
+	N      int          `json:"n"`
+	Edges  []edgeDoc    `json:"edges,omitempty"`
+	Matrix [][]*float64 `json:"matrix,omitempty"`
+}
+
+type pathDoc struct {
+	From      int      `json:"from"`
+	To        int      `json:"to"`
+	Reachable bool     `json:"reachable"`
+	Length    *float64 `json:"length"`
+	Path      []int    `json:"path"`
+}
+
+type solveDoc struct {
+	Algo      string       `json:"algo"`
+	N         int          `json:"n"`
+	ElapsedMS float64      `json:"elapsed_ms"`
+	Stats     SolverStats  `json:"stats"`
+	Dist      [][]*float64 `json:"dist"`
+	Paths     []pathDoc    `json:"paths,omitempty"`
+	Version   int          `json:"version"`
+	Cached    bool         `json:"cached"`
+}
+
+type apspServer struct {
+	mu      sync.Mutex
+	g       *Graph                // replaced, never modified, by an upload
+	results map[string]cacheEntry // by solver key, for the current upload
+	version int                   // counts uploads
+}
+
+func newAPSPServer() *apspServer {
+	return &apspServer{g: NewGraph(), results: make(map[string]cacheEntry)}
+}
+
+// snapshot returns the current graph and its upload version.
+func (s *apspServer) snapshot() (*Graph, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g, s.version
+}
+
+// result returns the algo result for the current graph together with the
+// upload version it belongs to. The solve runs outside mu so uploads and
+// reads are not held up; it is stored only if no upload happened meanwhile.
+func (s *apspServer) result(algo string) (res *APSPResult, cached bool, ver int, err error) {
+	s.mu.Lock()
+	g, ver := s.g, s.version
+	e, ok := s.results[algo]
+	s.mu.Unlock()
+	if ok {
+		return e.res, true, ver, e.err
+	}
+	sv, _ := solverByKey(algo)
+	res, err = runSolver(sv, "", "", g)
+	s.store(algo, ver, res, err)
+	return res, false, ver, err
+}
+
+// store keeps a result computed for upload ver unless the graph has been
+// replaced since; it reports whether the result was kept.
+func (s *apspServer) store(algo string, ver int, res *APSPResult, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.version != ver {
+		return false
+	}
+	s.results[algo] = cacheEntry{version: uint64(ver), res: res, err: err}
+	return true
+}
+
+func (s *apspServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /graph", s.handleGetGraph)
+	mux.HandleFunc("PUT /graph", s.handlePutGraph)
+	mux.HandleFunc("POST /graph", s.handlePutGraph)
+	mux.HandleFunc("POST /solve", s.handleSolve)
+	mux.HandleFunc("GET /path", s.handlePath)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// jsonDist encodes an unreachable distance as null.
+func jsonDist(d float64, ok bool) *float64 {
+	// JSON has no infinities; they only occur on the diagonal of some semirings
+	if !ok || math.IsInf(d, 0) {
+		return nil
+	}
+	return &d
+}
+
+func (s *apspServer) handleGetGraph(w http.ResponseWriter, r *http.Request) {
+	g, _ := s.snapshot()
+	doc := graphDoc{N: g.n, Edges: []edgeDoc{}}
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if w, ok := g.Edge(i, j); ok {
+				doc.Edges = append(doc.Edges, edgeDoc{From: i + 1, To: j + 1, Weight: w})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *apspServer) handlePutGraph(w http.ResponseWriter, r *http.Request) {
+	var doc graphDoc
+	dec := json.NewDecoder(io.LimitReader(r.Body, 64<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("некорректный JSON: %v", err))
+		return
+	}
+	g, err := doc.toGraph()
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.mu.Lock()
+	s.g = g
+	s.results = make(map[string]cacheEntry)
+	s.version++
+	ver := s.version
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]int{"n": g.n, "version": ver})
+}
+
+func (d graphDoc) toGraph() (*Graph, error) {
+	n := d.N
+	if d.Matrix != nil {
+		if n != 0 && n != len(d.Matrix) {
+			return nil, fmt.Errorf("n=%d не совпадает с размером матрицы %d", n, len(d.Matrix))
+		}
+		n = len(d.Matrix)
+	}
+	if n < 0 || n > maxGraphVertices {
+		return nil, fmt.Errorf("n должно быть от 0 до %d", maxGraphVertices)
+	}
+	g := NewGraph()
+	g.Resize(n)
+	// an edge may be given once, in the matrix or in the list
+	seen := make(map[[2]int]bool)
+	for i, row := range d.Matrix {
+		if len(row) != n {
+			return nil, fmt.Errorf("строка матрицы %d: %d значений, ожидалось %d", i+1, len(row), n)
+		}
+		for j, v := range row {
+			if i == j {
+				if v != nil && *v != 0 {
+					return nil, fmt.Errorf("ячейка (%d, %d): на диагонали допускается только 0", i+1, j+1)
+				}
+				continue
+			}
+			if v != nil {
+				if err := checkWeight(*v); err != nil {
+					return nil, fmt.Errorf("ячейка (%d, %d): %v", i+1, j+1, err)
+				}
+				g.SetEdge(i, j, *v, false)
+				seen[[2]int{i, j}] = true
+			}
+		}
+	}
+	for k, e := range d.Edges {
+		if e.From < 1 || e.To < 1 || e.From > n || e.To > n || e.From == e.To {
+			return nil, fmt.Errorf("дуга %d: некорректные вершины %d → %d", k+1, e.From, e.To)
+		}
+		key := [2]int{e.From - 1, e.To - 1}
+		if seen[key] {
+			return nil, fmt.Errorf("дуга %d: %d → %d задана повторно", k+1, e.From, e.To)
+		}
+		seen[key] = true
+		if err := checkWeight(e.Weight); err != nil {
+			return nil, fmt.Errorf("дуга %d: %v", k+1, err)
+		}
+		g.SetEdge(e.From-1, e.To-1, e.Weight, false)
+	}
+	return g, nil
+}
+
+func (s *apspServer) handleSolve(w http.ResponseWriter, r *http.Request) {
+	algo := r.URL.Query().Get("algo")
+	if algo == "" {
+		algo = "floyd"
+	}
+	if _, ok := solverByKey(algo); !ok {
+		writeJSONError(w, http.StatusBadRequest, errUnknownSolver(algo))
+		return
+	}
+	withPaths := r.URL.Query().Get("paths") == "1"
+
+	res, cached, ver, err := s.result(algo)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	n := res.N()
+	doc := solveDoc{Algo: algo, N: n, ElapsedMS: float64(res.Elapsed.Microseconds()) / 1000, Stats: res.Stats, Version: ver, Cached: cached}
+	doc.Dist = make([][]*float64, n)
+	for i := 0; i < n; i++ {
+		doc.Dist[i] = make([]*float64, n)
+		for j := 0; j < n; j++ {
+			d, ok := res.Dist(i, j)
+			doc.Dist[i][j] = jsonDist(d, ok)
+			if withPaths && i != j && ok {
+				doc.Paths = append(doc.Paths, pathDoc{From: i + 1, To: j + 1, Reachable: true, Length: doc.Dist[i][j], Path: res.Path(i, j)})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *apspServer) handlePath(w http.ResponseWriter, r *http.Request) {
+	from, errF := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errT := strconv.Atoi(r.URL.Query().Get("to"))
+	if errF != nil || errT != nil {
+		writeJSONError(w, http.StatusBadRequest, errors.New("нужны целые параметры from и to"))
+		return
+	}
+	res, _, _, err := s.result("floyd")
+	if n := res.N(); from < 1 || to < 1 || from > n || to > n {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("вершины должны быть от 1 до %d", n))
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	i, j := from-1, to-1
+	d, ok := res.Dist(i, j)
+	doc := pathDoc{From: from, To: to, Reachable: ok, Length: jsonDist(d, ok), Path: []int{}}
+	if ok {
+		doc.Path = res.Path(i, j)
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func cliServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "127.0.0.1:8080", "адрес для прослушивания")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	srv := &http.Server{Addr: *addr, Handler: newAPSPServer().routes(), ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(stderr, "apsp: слушаю http://%s\n", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(stderr, "apsp: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func doRequest(t *testing.T, h http.Handler, method, url, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%s %s: response is not JSON: %v\n%s", method, url, err, rec.Body.String())
+	}
+	return rec.Code, out
+}
+
+const serverTriangle = `{"n": 3, "edges": [{"from": 1, "to": 2, "weight": 1.5}, {"from": 2, "to": 3, "weight": -0.5}, {"from": 1, "to": 3, "weight": 2}]}`
+
+func TestServerSolveAndPath(t *testing.T) {
+	h := newAPSPServer().routes()
+	if code, out := doRequest(t, h, "PUT", "/graph", serverTriangle); code != http.StatusOK || out["version"] != 1.0 {
+		t.Fatalf("PUT /graph: %d %v", code, out)
+	}
+
+	code, out := doRequest(t, h, "POST", "/solve?paths=1", "")
+	if code != http.StatusOK {
+		t.Fatalf("POST /solve: %d %v", code, out)
+	}
+	dist := out["dist"].([]any)
+	if got := dist[0].([]any)[2]; got != 1.0 {
+		t.Errorf("dist 1→3 = %v, want 1", got)
+	}
+	if got := dist[2].([]any)[0]; got != nil {
+		t.Errorf("dist 3→1 = %v, want null", got)
+	}
+	if out["cached"] != false {
+		t.Errorf("first solve reported cached")
+	}
+	if _, out := doRequest(t, h, "POST", "/solve", ""); out["cached"] != true {
+		t.Errorf("second solve was not cached")
+	}
+
+	code, out = doRequest(t, h, "GET", "/path?from=1&to=3", "")
+	if code != http.StatusOK || out["length"] != 1.0 || out["reachable"] != true {
+		t.Fatalf("GET /path: %d %v", code, out)
+	}
+	if p := out["path"].([]any); len(p) != 3 || p[1] != 2.0 {
+		t.Errorf("path 1→3 = %v, want [1 2 3]", p)
+	}
+	code, out = doRequest(t, h, "GET", "/path?from=3&to=1", "")
+	if code != http.StatusOK || out["reachable"] != false || out["length"] != nil {
+		t.Errorf("GET /path 3→1: %d %v", code, out)
+	}
+
+	// a new upload drops the cached results
+	doRequest(t, h, "PUT", "/graph", `{"matrix": [[0, 4], [null, 0]]}`)
+	code, out = doRequest(t, h, "GET", "/path?from=1&to=2", "")
+	if code != http.StatusOK || out["length"] != 4.0 {
+		t.Errorf("GET /path after upload: %d %v", code, out)
+	}
+	if _, out := doRequest(t, h, "GET", "/graph", ""); out["n"] != 2.0 {
+		t.Errorf("GET /graph: %v", out)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	negCycle := `{"n": 2, "edges": [{"from": 1, "to": 2, "weight": 1}, {"from": 2, "to": 1, "weight": -2}]}`
+	tests := []struct {
+		name        string
+		graph       string
+		method, url string
+		body        string
+		want        int
+	}{
+		{"bad json", "", "PUT", "/graph", `{"n": `, http.StatusBadRequest},
+		{"unknown field", "", "PUT", "/graph", `{"n": 1, "nodes": []}`, http.StatusBadRequest},
+		{"bad edge", "", "PUT", "/graph", `{"n": 2, "edges": [{"from": 1, "to": 3, "weight": 1}]}`, http.StatusBadRequest},
+		{"diagonal", "", "PUT", "/graph", `{"matrix": [[1]]}`, http.StatusBadRequest},
+		{"repeated edge", "", "PUT", "/graph", `{"n": 2, "edges": [{"from": 1, "to": 2, "weight": 1}, {"from": 1, "to": 2, "weight": 3}]}`, http.StatusBadRequest},
+		{"edge in matrix and list", "", "PUT", "/graph", `{"matrix": [[0, 1], [null, 0]], "edges": [{"from": 1, "to": 2, "weight": 3}]}`, http.StatusBadRequest},
+		{"too many vertices", "", "PUT", "/graph", `{"n": 100000}`, http.StatusBadRequest},
+		{"ragged matrix", "", "PUT", "/graph", `{"matrix": [[0, 1], [0]]}`, http.StatusBadRequest},
+		{"unknown algo", serverTriangle, "POST", "/solve?algo=astar", "", http.StatusBadRequest},
+		{"path not numbers", serverTriangle, "GET", "/path?from=a&to=2", "", http.StatusBadRequest},
+		{"path out of range", serverTriangle, "GET", "/path?from=1&to=9", "", http.StatusNotFound},
+		{"path zero", serverTriangle, "GET", "/path?from=0&to=1", "", http.StatusNotFound},
+		{"dijkstra negative", serverTriangle, "POST", "/solve?algo=dijkstra", "", http.StatusUnprocessableEntity},
+		{"negative cycle solve", negCycle, "POST", "/solve", "", http.StatusUnprocessableEntity},
+		{"negative cycle path", negCycle, "GET", "/path?from=1&to=2", "", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAPSPServer().routes()
+			if tt.graph != "" {
+				if code, out := doRequest(t, h, "PUT", "/graph", tt.graph); code != http.StatusOK {
+					t.Fatalf("PUT /graph: %d %v", code, out)
+				}
+			}
+			code, out := doRequest(t, h, tt.method, tt.url, tt.body)
+			if code != tt.want {
+				t.Fatalf("%s %s: status %d, want %d (%v)", tt.method, tt.url, code, tt.want, out)
+			}
+			if _, ok := out["error"].(string); !ok {
+				t.Errorf("no error field: %v", out)
+			}
+		})
+	}
+}
+
+// A solve that finishes after a new upload must not be stored for it.
+func TestServerStaleResultDropped(t *testing.T) {
+	s := newAPSPServer()
+	h := s.routes()
+	doRequest(t, h, "PUT", "/graph", serverTriangle)
+	g, ver := s.snapshot()
+	sv, _ := solverByKey("floyd")
+	res, err := runSolver(sv, "", "", g)
+	doRequest(t, h, "PUT", "/graph", `{"n": 1}`)
+	if s.store("floyd", ver, res, err) {
+		t.Fatal("result of an old upload was stored")
+	}
+	if code, out := doRequest(t, h, "POST", "/solve", ""); code != http.StatusOK || out["n"] != 1.0 || out["cached"] != false {
+		t.Fatalf("POST /solve after upload: %d %v", code, out)
+	}
+	if !s.store("floyd", ver+1, res, err) {
+		t.Fatal("result of the current upload was not stored")
+	}
+}
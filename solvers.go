@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ---------------- Solver registry ----------------
+
+var (
+	errNegativeCycle = errors.New("Обнаружен отрицательный цикл — решения нет")
+	errNegativeEdges = errors.New("Есть отрицательные дуги — n×Дейкстра неприменим")
+)
+
+// SolverStats counts elementary operations performed by a solver.
+// Solvers take a *SolverStats that may be nil to skip counting.
+type SolverStats struct {
+	Iterations  int64 `json:"iterations"`  // inner-loop iterations
+	Relaxations int64 `json:"relaxations"` // candidate distances tried against the current best
+	Improved    int64 `json:"improved"`    // relaxations that lowered a distance
+	Comparisons int64 `json:"comparisons"` // distance comparisons, ∞ checks and heap order included
+	HeapOps     int64 `json:"heap_ops"`    // heap pushes and pops
+}
+
+func (st *SolverStats) String() string {
+	return fmt.Sprintf("итераций %d, релаксаций %d (успешных %d), сравнений %d, операций с кучей %d",
+		st.Iterations, st.Relaxations, st.Improved, st.Comparisons, st.HeapOps)
+}
+
+func (st *SolverStats) relax() {
+	if st != nil {
+		st.Relaxations++
+		st.Comparisons++
+	}
+}
+
+func (st *SolverStats) improve() {
+	if st != nil {
+		st.Improved++
+	}
+}
+
+func (st *SolverStats) iterate() {
+	if st != nil {
+		st.Iterations++
+	}
+}
+
+func (st *SolverStats) heapOp() {
+	if st != nil {
+		st.HeapOps++
+	}
+}
+
+func (st *SolverStats) compare(k int64) {
+	if st != nil {
+		st.Comparisons += k
+	}
+}
+
+// apspSolver is an all-pairs algorithm that can be run, compared and exported.
+// Run may return a result together with errNegativeCycle so callers can
+// inspect the cycle.
+type apspSolver struct {
+	Key         string
+	Title       string
+	NonNegative bool // refuses graphs with negative edges
+	Run         func(g *Graph, st *SolverStats) (*APSPResult, error)
+}
+
+var apspSolvers = []apspSolver{
+	{Key: "floyd", Title: "Флойд", Run: solveFloyd},
+	{Key: "dijkstra", Title: "n×Дейкстра", NonNegative: true, Run: solveDijkstraAll},
+	{Key: "bellman-ford", Title: "n×Беллман–Форд", Run: solveBellmanFordAll},
+}
+
+func solverByKey(key string) (apspSolver, bool) {
+	for _, s := range apspSolvers {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return apspSolver{}, false
+}
+
+func solverKeys() string {
+	keys := make([]string, len(apspSolvers))
+	for i, s := range apspSolvers {
+		keys[i] = s.Key
+	}
+	return strings.Join(keys, ", ")
+}
+
+func errUnknownSolver(key string) error {
+	return fmt.Errorf("неизвестный алгоритм %q (доступны: %s)", key, solverKeys())
+}
+
+func solveFloyd(g *Graph, st *SolverStats) (*APSPResult, error) {
+	dist, pred, neg := g.FloydWarshall(st)
+	res := &APSPResult{dist: dist, pred: pred, NegCycle: neg}
+	if neg {
+		return res, errNegativeCycle
+	}
+	return res, nil
+}
+
+func solveDijkstraAll(g *Graph, st *SolverStats) (*APSPResult, error) {
+	if g.HasNegativeEdge() {
+		return nil, errNegativeEdges
+	}
+	n := g.n
+	out := g.arcs()
+	res := &APSPResult{dist: NewMatrix(n, 0.0), pred: NewMatrix(n, -1)}
+	for s := 0; s < n; s++ {
+		d, p := dijkstraArcs(out, s, st)
+		copy(res.dist.Row(s), d)
+		copy(res.pred.Row(s), p)
+	}
+	return res, nil
+}
+
+func solveBellmanFordAll(g *Graph, st *SolverStats) (*APSPResult, error) {
+	n := g.n
+	res := &APSPResult{dist: NewMatrix(n, 0.0), pred: NewMatrix(n, -1)}
+	for s := 0; s < n; s++ {
+		d, p, neg := g.bellmanFordFrom(s, st)
+		if neg {
+			return nil, errNegativeCycle
+		}
+		copy(res.dist.Row(s), d)
+		copy(res.pred.Row(s), p)
+	}
+	return res, nil
+}
+
+// bellmanFordFrom computes single-source distances from s and reports
+// whether a negative cycle is reachable from s; st may be nil. prev[v] is -1
+// for unreachable v and s for s itself.
+func (g *Graph) bellmanFordFrom(s int, st *SolverStats) ([]float64, []int, bool) {
+	n := g.n
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range prev {
+		prev[i] = -1
+	}
+	prev[s] = s
+	for it := 0; it < n; it++ {
+		changed := false
+		for v := 0; v < n; v++ {
+			st.compare(1)
+			if prev[v] < 0 {
+				continue
+			}
+			for u := 0; u < n; u++ {
+				st.iterate()
+				w, ok := g.Edge(v, u)
+				st.compare(1)
+				if !ok {
+					continue
+				}
+				st.relax()
+				if prev[u] < 0 || dist[v]+w < dist[u] {
+					if it == n-1 {
+						return dist, prev, true
+					}
+					st.improve()
+					dist[u] = dist[v] + w
+					prev[u] = v
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return dist, prev, false
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ---------------- Exact weight arithmetic ----------------
+
+// weightArith is the arithmetic the generic solvers need from a weight type.
+// Parse reads an edge weight from Graph.WeightText: the text typed into a
+// matrix cell or CSV file when float64 could not hold it (1/3, more than ~15
+// significant digits), else the shortest decimal form of the float, so 0.1
+// becomes exactly 1/10. Weights from the editor, generators and DOT, GraphML
+// or JSON files are float64 only.
+type weightArith[T any] interface {
+	Zero() T
+	Add(a, b T) T
+	Less(a, b T) bool
+	Parse(s string) (T, error)
+	Format(a T) string
+	Float(a T) float64
+	MaxAbs() float64 // largest magnitude a path sum may reach
+}
+
+type weightKind struct {
+	Key   string
+	Title string
+}
+
+// weightKinds lists the arithmetic options; "float" is the plain float64
+// model used by the fast solvers.
+var weightKinds = []weightKind{
+	{Key: "float", Title: "float64"},
+	{Key: "int", Title: "целые (int64)"},
+	{Key: "decimal", Title: "десятичные (6 знаков)"},
+	{Key: "rat", Title: "дроби (big.Rat)"},
+}
+
+func weightKindByKey(key string) (weightKind, bool) {
+	for _, k := range weightKinds {
+		if k.Key == key {
+			return k, true
+		}
+	}
+	return weightKind{}, false
+}
+
+// weightTitle names a weight kind; "" means float64.
+func weightTitle(key string) string {
+	if k, ok := weightKindByKey(key); ok {
+		return k.Title
+	}
+	return weightKinds[0].Title
+}
+
+func errUnknownWeights(key string) error {
+	keys := make([]string, len(weightKinds))
+	for i, k := range weightKinds {
+		keys[i] = k.Key
+	}
+	return fmt.Errorf("неизвестный тип весов %q (доступны: %s)", key, strings.Join(keys, ", "))
+}
+
+// ---- int64 ----
+
+type intArith struct{}
+
+func (intArith) Zero() int64           { return 0 }
+func (intArith) Add(a, b int64) int64  { return a + b }
+func (intArith) Less(a, b int64) bool  { return a < b }
+func (intArith) Format(a int64) string { return strconv.FormatInt(a, 10) }
+func (intArith) Float(a int64) float64 { return float64(a) }
+func (intArith) MaxAbs() float64       { return math.MaxInt64 }
+func (intArith) Parse(s string) (int64, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return 0, err
+	}
+	if !r.IsInt() {
+		return 0, fmt.Errorf("вес %s не является целым", s)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("вес %s не помещается в int64", s)
+	}
+	return r.Num().Int64(), nil
+}
+
+// ---- fixed-point decimal ----
+
+const decimalDigits = 6
+
+var decimalScale = int64(math.Pow10(decimalDigits))
+
+// decimalArith stores values as int64 counts of 10⁻⁶.
+type decimalArith struct{}
+
+func (decimalArith) Zero() int64           { return 0 }
+func (decimalArith) Add(a, b int64) int64  { return a + b }
+func (decimalArith) Less(a, b int64) bool  { return a < b }
+func (decimalArith) Float(a int64) float64 { return float64(a) / float64(decimalScale) }
+func (decimalArith) MaxAbs() float64       { return math.MaxInt64 / float64(decimalScale) }
+
+func (decimalArith) Format(a int64) string {
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+	s := sign + strconv.FormatInt(a/decimalScale, 10)
+	if frac := a % decimalScale; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*d", decimalDigits, frac), "0")
+	}
+	return s
+}
+
+func (decimalArith) Parse(s string) (int64, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return 0, err
+	}
+	r.Mul(r, new(big.Rat).SetInt64(decimalScale))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("вес %s: больше %d знаков после запятой", s, decimalDigits)
+	}
+	if v := r.Num(); v.CmpAbs(big.NewInt(1e18)) >= 0 {
+		return 0, fmt.Errorf("вес %s слишком велик для десятичной арифметики", s)
+	}
+	return r.Num().Int64(), nil
+}
+
+// ---- big.Rat ----
+
+type ratArith struct{}
+
+func (ratArith) Zero() *big.Rat             { return new(big.Rat) }
+func (ratArith) Add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+func (ratArith) Less(a, b *big.Rat) bool    { return a.Cmp(b) < 0 }
+func (ratArith) Float(a *big.Rat) float64   { f, _ := a.Float64(); return f }
+func (ratArith) MaxAbs() float64            { return math.Inf(1) }
+
+// Format writes finite decimal fractions in decimal notation (3/10 → 0.3)
+// and everything else as a fraction.
+func (ratArith) Format(a *big.Rat) string {
+	if a.IsInt() {
+		return a.Num().String()
+	}
+	pow := big.NewInt(1)
+	ten := big.NewInt(10)
+	for k := 1; k <= 30; k++ {
+		pow.Mul(pow, ten)
+		if new(big.Int).Mod(pow, a.Denom()).Sign() == 0 {
+			return a.FloatString(k)
+		}
+	}
+	return a.RatString()
+}
+
+func (ratArith) Parse(s string) (*big.Rat, error) { return parseRat(s) }
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("вес %q не представим дробью", s)
+	}
+	return r, nil
+}
+
+// exactText returns the cell text s of weight v when the exact arithmetics
+// must read it instead of v: a fraction or a decimal longer than float64
+// keeps. "" means formatWeight(v) already has the exact value.
+func exactText(s string, v float64) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return ""
+	}
+	if fr, _ := new(big.Rat).SetString(formatWeight(v)); r.Cmp(fr) == 0 {
+		return ""
+	}
+	return s
+}
+
+// ---------------- Generic solvers ----------------
+
+// exactGraph is g with weights converted to T; has marks existing edges, so
+// no sentinel value is needed.
+type exactGraph[T any] struct {
+	n   int
+	ar  weightArith[T]
+	w   Matrix[T]
+	has Matrix[bool]
+	neg bool
+}
+
+var errWeightOverflow = errors.New("веса слишком велики: суммы путей переполнят int64")
+
+func convertGraph[T any](g *Graph, ar weightArith[T]) (*exactGraph[T], error) {
+	eg := &exactGraph[T]{n: g.n, ar: ar, w: NewMatrix(g.n, ar.Zero()), has: NewMatrix(g.n, false)}
+	var maxAbs float64
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if !g.HasEdge(i, j) {
+				continue
+			}
+			v, err := ar.Parse(g.WeightText(i, j))
+			if err != nil {
+				return nil, fmt.Errorf("дуга %d → %d: %v", i+1, j+1, err)
+			}
+			eg.w.Set(i, j, v)
+			eg.has.Set(i, j, true)
+			eg.neg = eg.neg || ar.Less(v, ar.Zero())
+			maxAbs = math.Max(maxAbs, math.Abs(ar.Float(v)))
+		}
+	}
+	// a Floyd candidate adds two paths of at most n-1 edges each
+	if maxAbs > ar.MaxAbs()/float64(2*max(g.n, 1)) {
+		return nil, errWeightOverflow
+	}
+	return eg, nil
+}
+
+// exactAPSP is an all-pairs result over T.
+type exactAPSP[T any] struct {
+	dist Matrix[T]
+	has  Matrix[bool]
+	pred Matrix[int]
+}
+
+func newExactAPSP[T any](n int, zero T) *exactAPSP[T] {
+	return &exactAPSP[T]{dist: NewMatrix(n, zero), has: NewMatrix(n, false), pred: NewMatrix(n, -1)}
+}
+
+func (eg *exactGraph[T]) floyd(st *SolverStats) (*exactAPSP[T], bool) {
+	n, ar := eg.n, eg.ar
+	r := newExactAPSP(n, ar.Zero())
+	r.dist, r.has = eg.w.Clone(), eg.has.Clone()
+	for i := 0; i < n; i++ {
+		r.has.Set(i, i, true)
+		r.dist.Set(i, i, ar.Zero())
+		for j := 0; j < n; j++ {
+			if r.has.At(i, j) {
+				r.pred.Set(i, j, i)
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			st.compare(1)
+			if !r.has.At(i, k) {
+				continue
+			}
+			dik := r.dist.At(i, k)
+			for j := 0; j < n; j++ {
+				st.iterate()
+				st.compare(1)
+				if !r.has.At(k, j) {
+					continue
+				}
+				cand := ar.Add(dik, r.dist.At(k, j))
+				st.relax()
+				if !r.has.At(i, j) || ar.Less(cand, r.dist.At(i, j)) {
+					st.improve()
+					r.dist.Set(i, j, cand)
+					r.has.Set(i, j, true)
+					r.pred.Set(i, j, r.pred.At(k, j))
+				}
+			}
+		}
+		// Until a cycle turns negative every distance is a simple path, which
+		// convertGraph bounded; past that point sums may overflow int64.
+		for i := 0; i < n; i++ {
+			if ar.Less(r.dist.At(i, i), ar.Zero()) {
+				return r, true
+			}
+		}
+	}
+	return r, false
+}
+
+// bellmanFord fills row s of r; false means a negative cycle is reachable.
+func (eg *exactGraph[T]) bellmanFord(r *exactAPSP[T], s int, st *SolverStats) bool {
+	n, ar := eg.n, eg.ar
+	dist, has, pred := r.dist.Row(s), r.has.Row(s), r.pred.Row(s)
+	dist[s], has[s], pred[s] = ar.Zero(), true, s
+	for it := 0; it < n; it++ {
+		changed := false
+		for v := 0; v < n; v++ {
+			st.compare(1)
+			if !has[v] {
+				continue
+			}
+			for u := 0; u < n; u++ {
+				st.iterate()
+				st.compare(1)
+				if v == u || !eg.has.At(v, u) {
+					continue
+				}
+				cand := ar.Add(dist[v], eg.w.At(v, u))
+				st.relax()
+				if !has[u] || ar.Less(cand, dist[u]) {
+					if it == n-1 {
+						return false
+					}
+					st.improve()
+					dist[u], has[u], pred[u] = cand, true, v
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return true
+}
+
+type exactItem[T any] struct {
+	v int
+	d T
+}
+
+type exactHeap[T any] struct {
+	items []exactItem[T]
+	ar    weightArith[T]
+	st    *SolverStats
+}
+
+func (h *exactHeap[T]) Len() int { return len(h.items) }
+func (h *exactHeap[T]) Less(i, j int) bool {
+	h.st.compare(1)
+	return h.ar.Less(h.items[i].d, h.items[j].d)
+}
+func (h *exactHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *exactHeap[T]) Push(x any)    { h.items = append(h.items, x.(exactItem[T])) }
+func (h *exactHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// dijkstra fills row s of r; weights must be non-negative.
+func (eg *exactGraph[T]) dijkstra(r *exactAPSP[T], s int, st *SolverStats) {
+	n, ar := eg.n, eg.ar
+	dist, has, pred := r.dist.Row(s), r.has.Row(s), r.pred.Row(s)
+	dist[s], has[s], pred[s] = ar.Zero(), true, s
+	pq := &exactHeap[T]{ar: ar, st: st}
+	heap.Push(pq, exactItem[T]{v: s, d: ar.Zero()})
+	st.heapOp()
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(exactItem[T])
+		st.heapOp()
+		st.compare(1)
+		if ar.Less(dist[it.v], it.d) {
+			continue
+		}
+		v := it.v
+		for u := 0; u < n; u++ {
+			if u == v || !eg.has.At(v, u) {
+				continue
+			}
+			st.iterate()
+			st.relax()
+			if cand := ar.Add(dist[v], eg.w.At(v, u)); !has[u] || ar.Less(cand, dist[u]) {
+				st.improve()
+				dist[u], has[u], pred[u] = cand, true, v
+				heap.Push(pq, exactItem[T]{v: u, d: cand})
+				st.heapOp()
+			}
+		}
+	}
+}
+
+// solveExact runs solver algo with weights converted to the exact type
+// weights ("int", "decimal" or "rat").
+func solveExact(g *Graph, algo, weights string, st *SolverStats) (*APSPResult, error) {
+	switch weights {
+	case "int":
+		return runExact(g, algo, weightArith[int64](intArith{}), st)
+	case "decimal":
+		return runExact(g, algo, weightArith[int64](decimalArith{}), st)
+	case "rat":
+		return runExact(g, algo, weightArith[*big.Rat](ratArith{}), st)
+	}
+	return nil, errUnknownWeights(weights)
+}
+
+func runExact[T any](g *Graph, algo string, ar weightArith[T], st *SolverStats) (*APSPResult, error) {
+	eg, err := convertGraph(g, ar)
+	if err != nil {
+		return nil, err
+	}
+	n := eg.n
+	var r *exactAPSP[T]
+	neg := false
+	switch algo {
+	case "floyd":
+		r, neg = eg.floyd(st)
+	case "dijkstra":
+		if eg.neg {
+			return nil, errNegativeEdges
+		}
+		r = newExactAPSP(n, ar.Zero())
+		for s := 0; s < n; s++ {
+			eg.dijkstra(r, s, st)
+		}
+	case "bellman-ford":
+		r = newExactAPSP(n, ar.Zero())
+		for s := 0; s < n; s++ {
+			if !eg.bellmanFord(r, s, st) {
+				return nil, errNegativeCycle
+			}
+		}
+	default:
+		return nil, errUnknownSolver(algo)
+	}
+
+	res := &APSPResult{NegCycle: neg, dist: NewMatrix(n, 0.0), pred: r.pred, text: NewMatrix(n, "∞")}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if r.has.At(i, j) {
+				res.dist.Set(i, j, ar.Float(r.dist.At(i, j)))
+				res.text.Set(i, j, ar.Format(r.dist.At(i, j)))
+			}
+		}
+	}
+	if neg {
+		return res, errNegativeCycle
+	}
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExactWeights(t *testing.T) {
+	cases := []struct {
+		name    string
+		rows    []string
+		weights string
+		want    string // distance 1 → 3
+		err     string
+	}{
+		{"0.1+0.2 decimal", []string{"0;0.1;", ";0;0.2", ";;0"}, "decimal", "0.3", ""},
+		{"0.1+0.2 rat", []string{"0;0,1;", ";0;0,2", ";;0"}, "rat", "0.3", ""},
+		{"1/3 rat", []string{"0;1/3;", ";0;1/3", ";;0"}, "rat", "2/3", ""},
+		{"1/3+2/3 rat", []string{"0;1/3;", ";0;2/3", ";;0"}, "rat", "1", ""},
+		{"1/3 decimal", []string{"0;1/3;", ";0;1", ";;0"}, "decimal", "", "больше 6 знаков"},
+		{"20 digits rat", []string{"0;0.10000000000000000001;", ";0;0.1", ";;0"}, "rat", "0.20000000000000000001", ""},
+		{"1e-6 decimal", []string{"0;0.000001;", ";0;-0.000002", ";;0"}, "decimal", "-0.000001", ""},
+		{"int", []string{"0;3;", ";0;-1", ";;0"}, "int", "2", ""},
+		{"int rejects decimals", []string{"0;1.5;", ";0;1", ";;0"}, "int", "", "не является целым"},
+		{"int rejects fractions", []string{"0;1;", ";0;1/3", ";;0"}, "int", "", "не является целым"},
+		{"int beyond float64", []string{"0;9007199254740993;", ";0;1", ";;0"}, "int", "9007199254740994", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := matrixGraph(t, c.rows...)
+			res, err := solveExact(g, "floyd", c.weights, nil)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("error %v, want one mentioning %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.text.At(0, 2); got != c.want {
+				t.Fatalf("dist 1 → 3 = %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+// Once a cycle is negative, Floyd keeps adding the cycle to itself; with
+// int64 weights near the convertGraph bound that sum wraps around and 4 → 1
+// got a large positive length although every edge is negative.
+func TestExactFloydStopsBeforeOverflow(t *testing.T) {
+	const w = "-1152921504606846974" // MaxInt64/8 - 1, the largest weight allowed for n = 4
+	g := matrixGraph(t, "0;"+w+";;", w+";0;"+w+";", ";"+w+";0;", ";;"+w+";0")
+	res, err := solveExact(g, "floyd", "int", nil)
+	if err != errNegativeCycle {
+		t.Fatalf("error %v, want a negative cycle", err)
+	}
+	for i := 0; i < g.n; i++ {
+		for j := 0; j < g.n; j++ {
+			if d := res.text.At(i, j); i != j && d != "∞" && !strings.HasPrefix(d, "-") {
+				t.Errorf("dist %d → %d = %s, an all-negative graph has no positive path", i+1, j+1, d)
+			}
+		}
+	}
+}
+
+func TestExactTextFollowsEdits(t *testing.T) {
+	g := matrixGraph(t, "0;1/3", ";0")
+	if got := floatToCell(g, 0, 1); got != "1/3" {
+		t.Fatalf("cell shows %q, want 1/3", got)
+	}
+	g.SetEdge(0, 1, 0.5, false)
+	if got := g.WeightText(0, 1); got != "0.5" {
+		t.Fatalf("weight text %q after SetEdge, want 0.5", got)
+	}
+	g.SetExactText(0, 1, "0,5")
+	if got := g.exact.At(0, 1); got != "" {
+		t.Fatalf("exact text %q kept for a weight float64 holds exactly", got)
+	}
+}